@@ -0,0 +1,150 @@
+// Command gms inspects and operates a message store.
+//
+//	gms -store <store> <command> [flags] [arguments]
+//
+// The store is a Postgres connection string (postgres://...), sqlite:<path to database file> or filelog:<directory>,
+// and defaults to $GMS_STORE. The commands are:
+//
+//	read stream <stream name>      prints the messages in a stream, one JSON object per line
+//	read category <category>       prints the messages in a category
+//	tail <category>                prints a category's messages as they are written, until interrupted
+//	write <file>                   writes the messages in a JSON file ("-" reads standard input)
+//	position get <subscriber ID>   prints the position a subscriber last committed
+//	position set <subscriber ID> <position>
+//	                               commits a position for a subscriber, which it starts from when it next starts
+//	stats                          prints the number of messages and the last global position for each category;
+//	                               it reads every message in the store to count them, so it's slow and heavy on a big store
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+
+	gms "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/repository/filelog"
+	"github.com/blackhatbrigade/gomessagestore/repository/sqlite"
+	_ "github.com/lib/pq" // registers the postgres driver
+	"github.com/sirupsen/logrus"
+)
+
+// errUsage is returned when the command line doesn't make sense; the usage has already been printed
+var errUsage = errors.New("invalid usage")
+
+// command runs one of gms's commands against an open message store
+type command func(ctx context.Context, ms gms.MessageStore, args []string, stdout, stderr io.Writer) error
+
+var commands = map[string]command{
+	"read":     read,
+	"tail":     tail,
+	"write":    write,
+	"position": position,
+	"stats":    stats,
+}
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	go func() {
+		<-interrupted
+		cancel()
+	}()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if err != errUsage {
+			fmt.Fprintf(os.Stderr, "gms: %s\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run parses the command line, opens the store and runs the command
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("gms", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	store := flags.String("store", os.Getenv("GMS_STORE"), "the message store: postgres://..., sqlite:<file> or filelog:<directory>")
+	verbose := flags.Bool("v", false, "log what the message store is doing")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: gms -store <store> read|tail|write|position|stats [flags] [arguments]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	if flags.NArg() < 1 || commands[flags.Arg(0)] == nil || *store == "" {
+		flags.Usage()
+		return errUsage
+	}
+
+	log := logrus.New()
+	log.Out = stderr
+	if !*verbose {
+		log.Out = ioutil.Discard
+		logrus.SetOutput(ioutil.Discard) // the repositories log through the standard logger too
+	}
+
+	ms, closeStore, err := openStore(ctx, *store, log)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	return commands[flags.Arg(0)](ctx, ms, flags.Args()[1:], stdout, stderr)
+}
+
+// openStore opens the message store named by a connection string or path, returning the function that closes it again
+func openStore(ctx context.Context, store string, log logrus.FieldLogger) (gms.MessageStore, func() error, error) {
+	switch {
+	case strings.HasPrefix(store, "postgres://"), strings.HasPrefix(store, "postgresql://"):
+		db, err := sql.Open("postgres", store)
+		if err != nil {
+			return nil, nil, err
+		}
+		return gms.NewMessageStore(db, log), db.Close, nil
+
+	case strings.HasPrefix(store, "sqlite:"):
+		db, err := sql.Open("sqlite3", "file:"+strings.TrimPrefix(store, "sqlite:")+"?_txlock=immediate")
+		if err != nil {
+			return nil, nil, err
+		}
+		repo, err := sqlite.NewSqliteRepository(ctx, db, log)
+		if err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+		return gms.NewMessageStoreFromRepository(repo, log), db.Close, nil
+
+	case strings.HasPrefix(store, "filelog:"):
+		repo, err := filelog.NewFileLogRepository(strings.TrimPrefix(store, "filelog:"), log)
+		if err != nil {
+			return nil, nil, err
+		}
+		return gms.NewMessageStoreFromRepository(repo, log), repo.Close, nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown store %q, expected postgres://..., sqlite:<file> or filelog:<directory>", store)
+}
+
+// parseFlags parses a command's flags, printing its usage when they don't make sense
+func parseFlags(flags *flag.FlagSet, args []string, stderr io.Writer, usage string) error {
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: gms %s\n", usage)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	return nil
+}
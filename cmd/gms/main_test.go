@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const messages = `
+{"id":"10000000-0000-0000-0000-000000000001","streamName":"account-20000000-0000-0000-0000-000000000001","messageType":"Opened","data":{"owner":"a"}}
+{"streamName":"account-20000000-0000-0000-0000-000000000001","messageType":"Deposited","data":{"amount":5},"metadata":{"by":"b"},"expectedVersion":0}
+{"streamName":"account:command-20000000-0000-0000-0000-000000000001","messageType":"Close","data":{}}
+{"streamName":"account-20000000-0000-0000-0000-000000000002","messageType":"Opened","data":{"owner":"c"}}
+`
+
+// stores makes a store of each kind that works without a server, returning them with the function that removes them
+func stores(t *testing.T) (map[string]string, func()) {
+	dir, err := ioutil.TempDir("", "gms")
+	if err != nil {
+		t.Fatalf("failed to create a temporary directory: %s", err)
+	}
+
+	return map[string]string{
+		"filelog": "filelog:" + filepath.Join(dir, "log"),
+		"sqlite":  "sqlite:" + filepath.Join(dir, "messages.db"),
+	}, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+// runGms runs a command line against a store, returning what it printed
+func runGms(ctx context.Context, store string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	err := run(ctx, append([]string{"-store", store}, args...), &stdout, &stderr)
+	return stdout.String(), err
+}
+
+// decodeLines decodes each line of JSON the command printed
+func decodeLines(t *testing.T, output string) []map[string]interface{} {
+	var msgs []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var msg map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("failed to decode %q: %s", line, err)
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return msgs
+}
+
+func TestCommands(t *testing.T) {
+	all, cleanup := stores(t)
+	defer cleanup()
+
+	for name, store := range all {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			ctx := context.Background()
+
+			file := filepath.Join(filepath.Dir(strings.SplitN(store, ":", 2)[1]), name+".json")
+			assert.Nil(ioutil.WriteFile(file, []byte(messages), 0644))
+
+			output, err := runGms(ctx, store, "write", file)
+			assert.Nil(err)
+			ids := strings.Fields(output)
+			assert.Len(ids, 4)
+			assert.Equal("10000000-0000-0000-0000-000000000001", ids[0])
+
+			_, err = runGms(ctx, store, "write", file)
+			assert.NotNil(err, "the first message has already been written")
+
+			output, err = runGms(ctx, store, "read", "stream", "-batch-size", "1", "account-20000000-0000-0000-0000-000000000001")
+			assert.Nil(err)
+			msgs := decodeLines(t, output)
+			if assert.Len(msgs, 2) {
+				assert.Equal("Opened", msgs[0]["messageType"])
+				assert.Equal(map[string]interface{}{"amount": float64(5)}, msgs[1]["data"])
+				assert.Equal(map[string]interface{}{"by": "b"}, msgs[1]["metadata"])
+			}
+
+			output, err = runGms(ctx, store, "read", "stream", "-since-version", "1", "account-20000000-0000-0000-0000-000000000001")
+			assert.Nil(err)
+			assert.Len(decodeLines(t, output), 1)
+
+			output, err = runGms(ctx, store, "read", "category", "-since-position", "2", "account")
+			assert.Nil(err)
+			msgs = decodeLines(t, output)
+			if assert.Len(msgs, 2) {
+				assert.Equal("Deposited", msgs[0]["messageType"])
+				assert.Equal("20000000-0000-0000-0000-000000000002", msgs[1]["entityId"])
+			}
+
+			output, err = runGms(ctx, store, "position", "get", "someSubscriber")
+			assert.Nil(err)
+			assert.Equal("0\n", output)
+
+			_, err = runGms(ctx, store, "position", "set", "someSubscriber", "3")
+			assert.Nil(err)
+			output, err = runGms(ctx, store, "position", "get", "someSubscriber")
+			assert.Nil(err)
+			assert.Equal("3\n", output)
+
+			output, err = runGms(ctx, store, "stats")
+			assert.Nil(err)
+			lines := strings.Split(strings.TrimSpace(output), "\n")
+			if assert.Len(lines, 4) {
+				assert.Equal([]string{"account", "3", "4"}, strings.Fields(lines[1])[:3])
+				assert.Equal([]string{"account:command", "1", "3"}, strings.Fields(lines[2])[:3])
+				assert.Equal([]string{"someSubscriber+position", "1", "5"}, strings.Fields(lines[3])[:3])
+			}
+		})
+	}
+}
+
+func TestTail(t *testing.T) {
+	all, cleanup := stores(t)
+	defer cleanup()
+	store := all["filelog"]
+
+	file := filepath.Join(filepath.Dir(strings.TrimPrefix(store, "filelog:")), "messages.json")
+	assert.Nil(t, ioutil.WriteFile(file, []byte(messages), 0644))
+	_, err := runGms(context.Background(), store, "write", file)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	output, err := runGms(ctx, store, "tail", "-poll", "time.Millisecond", "account")
+	assert.Equal(t, errUsage, err, "the poll time is a duration")
+
+	output, err = runGms(ctx, store, "tail", "-poll", "1ms", "-since-position", "4", "account")
+	assert.Nil(t, err)
+	msgs := decodeLines(t, output)
+	if assert.Len(t, msgs, 1) {
+		assert.Equal(t, float64(4), msgs[0]["globalPosition"])
+	}
+}
+
+func TestUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{{
+		name: "when there is no command",
+		args: []string{"-store", "filelog:somewhere"},
+	}, {
+		name: "when the command is unknown",
+		args: []string{"-store", "filelog:somewhere", "frobnicate"},
+	}, {
+		name: "when there is no store",
+		args: []string{"-store", "", "stats"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := run(context.Background(), test.args, &stdout, &stderr)
+			assert.Equal(t, errUsage, err)
+			assert.Contains(t, stderr.String(), "usage: gms")
+		})
+	}
+}
+
+func TestUnknownStore(t *testing.T) {
+	_, err := runGms(context.Background(), "mysql://somewhere", "stats")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown store")
+}
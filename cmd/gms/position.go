@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	gms "github.com/blackhatbrigade/gomessagestore"
+)
+
+// position reads or commits the position in a subscriber's +position stream
+func position(ctx context.Context, ms gms.MessageStore, args []string, stdout, stderr io.Writer) error {
+	usage := func() error {
+		fmt.Fprintln(stderr, "usage: gms position get <subscriber ID>\n       gms position set <subscriber ID> <position>")
+		return errUsage
+	}
+	if len(args) < 2 {
+		return usage()
+	}
+
+	switch {
+	case args[0] == "get" && len(args) == 2:
+		position, err := gms.GetSubscriberPosition(ctx, ms, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, position)
+		return nil
+
+	case args[0] == "set" && len(args) == 3:
+		position, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil || position < 0 {
+			return fmt.Errorf("invalid position %q", args[2])
+		}
+		return gms.SetSubscriberPosition(ctx, ms, args[1], position)
+	}
+
+	return usage()
+}
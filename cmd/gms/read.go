@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	gms "github.com/blackhatbrigade/gomessagestore"
+)
+
+// read prints every message in a stream or category, one JSON object per line, reading a batch at a time
+func read(ctx context.Context, ms gms.MessageStore, args []string, stdout, stderr io.Writer) error {
+	if len(args) < 1 || (args[0] != "stream" && args[0] != "category") {
+		fmt.Fprintln(stderr, "usage: gms read stream|category [flags] <name>")
+		return errUsage
+	}
+	kind := args[0]
+
+	flags := flag.NewFlagSet("read "+kind, flag.ContinueOnError)
+	batchSize := flags.Int("batch-size", 1000, "the number of messages read at a time")
+	var since *int64
+	if kind == "stream" {
+		since = flags.Int64("since-version", 0, "the version of the first message printed")
+	} else {
+		since = flags.Int64("since-position", 0, "the global position of the first message printed")
+	}
+	if err := parseFlags(flags, args[1:], stderr, "read "+kind+" [flags] <name>"); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errUsage
+	}
+	name := flags.Arg(0)
+
+	opts := []gms.GetOption{gms.Category(name), gms.SincePosition(*since)}
+	if kind == "stream" {
+		opts = []gms.GetOption{gms.GenericStream(name), gms.SinceVersion(*since)}
+	}
+
+	msgs := ms.Iterate(ctx, append(opts, gms.BatchSize(*batchSize))...)
+	defer msgs.Close()
+
+	encoder := json.NewEncoder(stdout)
+	for msgs.Next() {
+		if err := encoder.Encode(msgs.Message()); err != nil {
+			return err
+		}
+	}
+
+	return msgs.Err()
+}
+
+// tail prints the messages in a category as they are written, until it's interrupted
+func tail(ctx context.Context, ms gms.MessageStore, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("tail", flag.ContinueOnError)
+	since := flags.Int64("since-position", 0, "the global position of the first message printed")
+	pollTime := flags.Duration("poll", time.Second, "the time between reads once it has caught up")
+	if err := parseFlags(flags, args, stderr, "tail [flags] <category>"); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errUsage
+	}
+
+	position := *since
+	for {
+		msgs, err := ms.Get(ctx, gms.Category(flags.Arg(0)), gms.SincePosition(position))
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		if err := printMessages(stdout, msgs); err != nil {
+			return err
+		}
+		if len(msgs) > 0 {
+			position = msgs[len(msgs)-1].Position() + 1
+			continue // there may be more waiting
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(*pollTime):
+		}
+	}
+}
+
+// printMessages writes each message as a line of JSON
+func printMessages(stdout io.Writer, msgs []gms.Message) error {
+	encoder := json.NewEncoder(stdout)
+	for _, msg := range msgs {
+		if err := encoder.Encode(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
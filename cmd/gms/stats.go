@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	gms "github.com/blackhatbrigade/gomessagestore"
+)
+
+// categoryStats is what stats keeps track of for each category
+type categoryStats struct {
+	messages     int
+	lastPosition int64
+	lastWritten  time.Time
+}
+
+// stats reads every message in the store, printing how many there are in each category and when the last was written.
+// There's no way to count messages without reading them through a repository, so it reads all of them, data and all.
+func stats(ctx context.Context, ms gms.MessageStore, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	batchSize := flags.Int("batch-size", 1000, "the number of messages read at a time")
+	usage := "stats [flags]\n\n" +
+		"stats reads every message in the store, data and metadata included, just to count them. On a big store that's slow\n" +
+		"and loads the database, so run it against a replica, or with a bigger -batch-size to make fewer round trips."
+	if err := parseFlags(flags, args, stderr, usage); err != nil {
+		return err
+	}
+
+	categories := make(map[string]*categoryStats)
+	msgs := ms.Iterate(ctx, gms.All(), gms.BatchSize(*batchSize))
+	defer msgs.Close()
+	for msgs.Next() {
+		msg := msgs.Message()
+		category, written := categoryOf(msg)
+		if categories[category] == nil {
+			categories[category] = &categoryStats{}
+		}
+		categories[category].messages++
+		categories[category].lastPosition = msg.Position()
+		categories[category].lastWritten = written
+	}
+	if err := msgs.Err(); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	table := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "CATEGORY\tMESSAGES\tLAST POSITION\tLAST WRITTEN")
+	for _, name := range names {
+		category := categories[name]
+		fmt.Fprintf(table, "%s\t%d\t%d\t%s\n", name, category.messages, category.lastPosition, category.lastWritten.Format(time.RFC3339))
+	}
+
+	return table.Flush()
+}
+
+// categoryOf finds the category a message was read from, and when it was written
+func categoryOf(msg gms.Message) (string, time.Time) {
+	switch message := msg.(type) {
+	case gms.Command:
+		return message.StreamCategory + ":command", message.Time
+	case gms.Event:
+		return message.StreamCategory, message.Time
+	}
+
+	return "", time.Time{} // only the default converters are used, and they only make commands and events
+}
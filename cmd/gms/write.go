@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	gms "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+)
+
+// messageToWrite is a message as it's given to write; the ID is made up when it's left out
+type messageToWrite struct {
+	ID              uuid.UUID       `json:"id"`
+	StreamName      string          `json:"streamName"`
+	MessageType     string          `json:"messageType"`
+	Data            json.RawMessage `json:"data"`
+	Metadata        json.RawMessage `json:"metadata"`
+	ExpectedVersion *int64          `json:"expectedVersion"` // when set, the message is written with AtPosition
+}
+
+// write writes the messages in a file, which holds a JSON array of messages or one message after another
+func write(ctx context.Context, ms gms.MessageStore, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("write", flag.ContinueOnError)
+	if err := parseFlags(flags, args, stderr, "write <file>"); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errUsage
+	}
+
+	var input io.Reader = os.Stdin
+	if flags.Arg(0) != "-" {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	toWrite, err := decodeMessages(input)
+	if err != nil {
+		return err
+	}
+
+	for i, m := range toWrite {
+		if m.ID == gms.NilUUID {
+			m.ID = gms.NewID()
+		}
+
+		msgs := gms.MsgEnvelopesToMessages([]*repository.MessageEnvelope{{
+			ID:          m.ID,
+			StreamName:  m.StreamName,
+			MessageType: m.MessageType,
+			Data:        m.Data,
+			Metadata:    m.Metadata,
+		}})
+		if len(msgs) != 1 {
+			return fmt.Errorf("message %d: can't be written to %q", i+1, m.StreamName)
+		}
+
+		var opts []gms.WriteOption
+		if m.ExpectedVersion != nil {
+			opts = append(opts, gms.AtPosition(*m.ExpectedVersion))
+		}
+		if err := ms.Write(ctx, msgs[0], opts...); err != nil {
+			return fmt.Errorf("message %d: %s", i+1, err)
+		}
+
+		fmt.Fprintln(stdout, m.ID)
+	}
+
+	return nil
+}
+
+// decodeMessages reads either a JSON array of messages or a sequence of them (like newline delimited JSON)
+func decodeMessages(input io.Reader) ([]messageToWrite, error) {
+	data, err := ioutil.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs []messageToWrite
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err := json.Unmarshal(trimmed, &msgs)
+		return msgs, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var msg messageToWrite
+		if err := decoder.Decode(&msg); err == io.EOF {
+			return msgs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/blackhatbrigade/gomessagestore/internal/jsonenvelope"
 	"github.com/blackhatbrigade/gomessagestore/repository"
 	"github.com/blackhatbrigade/gomessagestore/uuid"
 )
@@ -116,8 +117,8 @@ func (c Command) MarshalJSON() ([]byte, error) {
 		"messageType":    c.MessageType,
 		"messageVersion": c.MessageVersion,
 		"globalPosition": c.GlobalPosition,
-		"data":           json.RawMessage(string(c.Data)),
-		"metadata":       json.RawMessage(string(c.Metadata)),
+		"data":           jsonenvelope.Raw(c.Data),
+		"metadata":       jsonenvelope.Raw(c.Metadata),
 		"time":           c.Time,
 	}
 
@@ -1,12 +1,14 @@
 package gomessagestore_test
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
 
 	. "github.com/blackhatbrigade/gomessagestore"
 	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
 )
 
 func getSampleCommandMissing(key string) Command {
@@ -163,3 +165,54 @@ func TestCommandToEnvelope(t *testing.T) {
 		})
 	}
 }
+
+func TestCommandMarshalJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata []byte
+	}{{
+		name:     "with metadata",
+		metadata: []byte(`{"userId":"1"}`),
+	}, {
+		name: "without metadata",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := NewCommand(uuid1, uuid2, "test cat", "test type", []byte(`{"Field1":"a"}`), test.metadata)
+			cmd.MessageVersion = 3
+			cmd.GlobalPosition = 7
+			cmd.Time = time.Date(2019, 4, 1, 12, 0, 0, 0, time.UTC)
+
+			data, err := json.Marshal(cmd)
+			if err != nil {
+				t.Fatalf("Failed to marshal a command: %s", err)
+			}
+
+			var holder struct {
+				ID             uuid.UUID       `json:"id"`
+				EntityID       uuid.UUID       `json:"entityId"`
+				StreamCategory string          `json:"streamCategory"`
+				MessageType    string          `json:"messageType"`
+				MessageVersion int64           `json:"messageVersion"`
+				GlobalPosition int64           `json:"globalPosition"`
+				Data           json.RawMessage `json:"data"`
+				Metadata       json.RawMessage `json:"metadata"`
+				Time           time.Time       `json:"time"`
+			}
+			panicIf(json.Unmarshal(data, &holder))
+
+			roundTripped := NewCommand(holder.ID, holder.EntityID, holder.StreamCategory, holder.MessageType, []byte(holder.Data), nil)
+			if string(holder.Metadata) != "null" {
+				roundTripped.Metadata = []byte(holder.Metadata)
+			}
+			roundTripped.MessageVersion = holder.MessageVersion
+			roundTripped.GlobalPosition = holder.GlobalPosition
+			roundTripped.Time = holder.Time
+
+			if !reflect.DeepEqual(cmd, roundTripped) {
+				t.Errorf("Command changed on its way through JSON\nExpected: %+v\nActual: %+v\nJSON: %s", cmd, roundTripped, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package gomessagestore
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+//go:generate bash -c "${GOPATH}/bin/mockgen github.com/blackhatbrigade/gomessagestore EntityStore > mocks/entity_store.go"
+//go:generate bash -c "${GOPATH}/bin/mockgen github.com/blackhatbrigade/gomessagestore SnapshotStore > mocks/snapshot_store.go"
+
+// EntityStore fetches projected entities, caching them by stream so that later fetches only apply messages written since the last fetch
+//
+// The version returned alongside an entity is the version of the last message read from its stream (-1 when the stream has no messages),
+// and can be handed straight to AtPosition when writing the outcome of a decision made with that entity:
+//
+//	entity, version, err := entityStore.Fetch(ctx, "account", accountID)
+//	...
+//	err = ms.Write(ctx, newEvent, AtPosition(version))
+//
+// An EntityStore is safe for concurrent use. Cached entities are handed to every caller fetching the same stream and are the starting
+// point for the next fetch, so treat them as read-only. With a projector whose reducers change state in place (see StateFactory),
+// give the store EntityCopy, so the next fetch projects onto a copy and never changes an entity that was already handed out.
+// A fetch that fails drops its stream from the cache, so nothing half projected is ever started from again.
+type EntityStore interface {
+	Fetch(ctx context.Context, category string, entityID uuid.UUID) (entity interface{}, version int64, err error) // fetches the entity for a category and entity ID
+	FetchStream(ctx context.Context, stream string) (entity interface{}, version int64, err error)                 // fetches the entity for a stream
+}
+
+// SnapshotStore persists projected entities so an EntityStore can skip replaying a stream from the beginning after a cache miss
+type SnapshotStore interface {
+	Get(ctx context.Context, stream string) (entity interface{}, version int64, err error) // returns a version of -1 when no snapshot exists for the stream
+	Put(ctx context.Context, stream string, entity interface{}, version int64) error
+}
+
+// EntityStoreOption is used for configuring an entity store
+type EntityStoreOption func(store *entityStore)
+
+// entityStore the base entity store struct
+type entityStore struct {
+	projector        Projector
+	cacheSize        int
+	snapshots        SnapshotStore
+	snapshotInterval int64
+	copyEntity       func(entity interface{}) interface{}
+	log              logrus.FieldLogger
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element // cached entities by stream name
+	recency *list.List               // most recently used at the front
+}
+
+// cachedEntity is what an entityStore keeps for each stream
+type cachedEntity struct {
+	stream          string
+	entity          interface{}
+	version         int64
+	snapshotVersion int64 // the version of the last snapshot taken, -1 when none has been taken
+}
+
+// CreateEntityStore creates a new EntityStore on top of a Projector
+func (ms *msgStore) CreateEntityStore(projector Projector, opts ...EntityStoreOption) (EntityStore, error) {
+	if projector == nil {
+		return nil, ErrEntityStoreNeedsProjector
+	}
+
+	store := &entityStore{
+		projector:        projector,
+		cacheSize:        1000,
+		snapshotInterval: 100,
+		entries:          make(map[string]*list.Element),
+		recency:          list.New(),
+		log:              ms.log,
+	}
+
+	for _, option := range opts {
+		option(store)
+	}
+
+	if store.cacheSize < 1 {
+		return nil, ErrInvalidEntityCacheSize
+	}
+
+	if store.snapshotInterval < 1 {
+		return nil, ErrInvalidSnapshotInterval
+	}
+
+	return store, nil
+}
+
+// EntityCacheSize sets how many entities are kept in memory before the least recently used is evicted (default 1000)
+func EntityCacheSize(size int) EntityStoreOption {
+	return func(store *entityStore) {
+		store.cacheSize = size
+	}
+}
+
+// EntitySnapshots backs the in memory cache with a SnapshotStore, consulted on cache misses and updated as entities move forward
+func EntitySnapshots(snapshots SnapshotStore) EntityStoreOption {
+	return func(store *entityStore) {
+		store.snapshots = snapshots
+	}
+}
+
+// SnapshotInterval sets how many versions an entity must move forward before a new snapshot is stored (default 100)
+func SnapshotInterval(interval int64) EntityStoreOption {
+	return func(store *entityStore) {
+		store.snapshotInterval = interval
+	}
+}
+
+// EntityCopy sets how a cached entity is copied before the messages written since it was cached are projected onto it; needed
+// when reducers change state in place, so entities already handed out aren't changed by later fetches
+func EntityCopy(copy func(entity interface{}) interface{}) EntityStoreOption {
+	return func(store *entityStore) {
+		store.copyEntity = copy
+	}
+}
+
+// Fetch projects the entity for a category and entity ID, returning it along with the version of its stream
+func (store *entityStore) Fetch(ctx context.Context, category string, entityID uuid.UUID) (interface{}, int64, error) {
+	return store.FetchStream(ctx, category+"-"+entityID.String())
+}
+
+// FetchStream projects the entity for a stream, returning it along with the version of the stream
+func (store *entityStore) FetchStream(ctx context.Context, stream string) (interface{}, int64, error) {
+	cached, found, err := store.lookup(ctx, stream)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	var projection *Projection
+	if found {
+		entity := cached.entity
+		if store.copyEntity != nil {
+			entity = store.copyEntity(entity)
+		}
+		projection, err = store.projector.RunOnStreamFrom(ctx, stream, entity, cached.version)
+	} else {
+		projection, err = store.projector.RunOnStreamWithVersion(ctx, stream)
+	}
+	if err != nil {
+		store.forget(stream) // a reducer may have failed part way through changing the cached entity
+		return nil, -1, err
+	}
+	entity, version := projection.State, projection.Version
+
+	updated := &cachedEntity{
+		stream:          stream,
+		entity:          entity,
+		version:         version,
+		snapshotVersion: cached.snapshotVersion,
+	}
+
+	if store.snapshots != nil && version-updated.snapshotVersion >= store.snapshotInterval {
+		if err := store.snapshots.Put(ctx, stream, entity, version); err != nil {
+			// the entity is still good, and the next fetch will try to snapshot it again
+			store.log.WithError(err).WithField("stream", stream).Error("Failure in entity_store.go::FetchStream")
+		} else {
+			updated.snapshotVersion = version
+		}
+	}
+
+	store.remember(updated)
+
+	return entity, version, nil
+}
+
+// lookup finds the best known starting point for a stream, first checking the cache and then any snapshot
+func (store *entityStore) lookup(ctx context.Context, stream string) (*cachedEntity, bool, error) {
+	store.mutex.Lock()
+	if element, ok := store.entries[stream]; ok {
+		store.recency.MoveToFront(element)
+		cached := *element.Value.(*cachedEntity) // copy so concurrent fetches don't step on each other
+		store.mutex.Unlock()
+		return &cached, true, nil
+	}
+	store.mutex.Unlock()
+
+	if store.snapshots != nil {
+		entity, version, err := store.snapshots.Get(ctx, stream)
+		if err != nil {
+			return nil, false, err
+		}
+		if version >= 0 {
+			return &cachedEntity{
+				stream:          stream,
+				entity:          entity,
+				version:         version,
+				snapshotVersion: version,
+			}, true, nil
+		}
+	}
+
+	return &cachedEntity{
+		stream:          stream,
+		version:         -1,
+		snapshotVersion: -1,
+	}, false, nil
+}
+
+// forget drops a stream's entity from the cache
+func (store *entityStore) forget(stream string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if element, ok := store.entries[stream]; ok {
+		store.recency.Remove(element)
+		delete(store.entries, stream)
+	}
+}
+
+// remember caches an entity, keeping whichever of the new and existing entries is further along, and evicts the least recently used entries
+func (store *entityStore) remember(updated *cachedEntity) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if element, ok := store.entries[updated.stream]; ok {
+		existing := element.Value.(*cachedEntity)
+		if existing.version <= updated.version {
+			element.Value = updated
+		}
+		store.recency.MoveToFront(element)
+		return
+	}
+
+	store.entries[updated.stream] = store.recency.PushFront(updated)
+	for store.recency.Len() > store.cacheSize {
+		oldest := store.recency.Back()
+		store.recency.Remove(oldest)
+		delete(store.entries, oldest.Value.(*cachedEntity).stream)
+	}
+}
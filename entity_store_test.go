@@ -0,0 +1,411 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	mock_gomessagestore "github.com/blackhatbrigade/gomessagestore/mocks"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	mock_repository "github.com/blackhatbrigade/gomessagestore/repository/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+)
+
+func getEntityStoreForTest(t *testing.T, ctrl *gomock.Controller, opts ...EntityStoreOption) (*mock_repository.MockRepository, EntityStore) {
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	var logrusLogger = logrus.New()
+	logrusLogger.Out = ioutil.Discard
+	myMessageStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+
+	myprojector, err := myMessageStore.CreateProjector(
+		DefaultState(mockDataStructure{}),
+		WithReducer(new(mockReducer1)),
+		WithReducer(new(mockReducer2)),
+	)
+	panicIf(err)
+
+	entityStore, err := myMessageStore.CreateEntityStore(myprojector, opts...)
+	if err != nil {
+		t.Fatalf("Error creating entity store: %s", err)
+	}
+
+	return mockRepo, entityStore
+}
+
+func TestEntityStoreFetchReturnsEntityAndVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo, entityStore := getEntityStoreForTest(t, ctrl)
+
+	mockEventEnvs := getSampleEventsAsEnvelopes()
+	expectedEvents := getSampleEvents()
+	ctx := context.Background()
+
+	gomock.InOrder(
+		mockRepo.
+			EXPECT().
+			GetStreamVersion(ctx, mockEventEnvs[0].StreamName).
+			Return(int64(8), nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStreamSince(ctx, mockEventEnvs[0].StreamName, int64(0), 1000, "Event MessageType 1", "Event MessageType 2").
+			Return(mockEventEnvs, nil),
+		mockRepo.
+			EXPECT().
+			GetStreamVersion(ctx, mockEventEnvs[0].StreamName).
+			Return(int64(8), nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStreamSince(ctx, mockEventEnvs[0].StreamName, int64(9), 1000, "Event MessageType 1", "Event MessageType 2").
+			Return([]*repository.MessageEnvelope{}, nil),
+	)
+
+	for i := 0; i < 2; i++ {
+		entity, version, err := entityStore.Fetch(ctx, expectedEvents[0].StreamCategory, expectedEvents[0].EntityID)
+		if err != nil {
+			t.Fatalf("Error fetching entity: %s", err)
+		}
+
+		if version != 8 {
+			t.Errorf("Wrong version on fetch %d\nExpected: 8\n     Got: %d\n", i, version)
+		}
+
+		state, ok := entity.(mockDataStructure)
+		if !ok {
+			t.Fatalf("Received incorrect type of entity back: %T", entity)
+		}
+		if state.MockReducer1CallCount != 1 || state.MockReducer2CallCount != 1 {
+			t.Errorf("Reducers were not applied exactly once each on fetch %d: %+v", i, state)
+		}
+	}
+}
+
+func TestEntityStoreFetchAppliesOnlyNewMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo, entityStore := getEntityStoreForTest(t, ctrl)
+
+	mockEventEnvs := getSampleEventsAsEnvelopes()
+	ctx := context.Background()
+	stream := mockEventEnvs[0].StreamName
+
+	gomock.InOrder(
+		mockRepo.
+			EXPECT().
+			GetStreamVersion(ctx, stream).
+			Return(mockEventEnvs[0].Version, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStreamSince(ctx, stream, int64(0), 1000, "Event MessageType 1", "Event MessageType 2").
+			Return(mockEventEnvs[:1], nil),
+		mockRepo.
+			EXPECT().
+			GetStreamVersion(ctx, stream).
+			Return(mockEventEnvs[1].Version, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStreamSince(ctx, stream, mockEventEnvs[0].Version+1, 1000, "Event MessageType 1", "Event MessageType 2").
+			Return(mockEventEnvs[1:], nil),
+	)
+
+	_, version, err := entityStore.FetchStream(ctx, stream)
+	if err != nil || version != mockEventEnvs[0].Version {
+		t.Fatalf("First fetch failed, version: %d err: %v", version, err)
+	}
+
+	entity, version, err := entityStore.FetchStream(ctx, stream)
+	if err != nil || version != mockEventEnvs[1].Version {
+		t.Fatalf("Second fetch failed, version: %d err: %v", version, err)
+	}
+
+	state := entity.(mockDataStructure)
+	if state.MockReducer1CallCount != 1 || state.MockReducer2CallCount != 1 {
+		t.Errorf("Reducers were not applied exactly once each: %+v", state)
+	}
+}
+
+func TestEntityStoreFetchOfEmptyStreamReturnsDefaultState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo, entityStore := getEntityStoreForTest(t, ctrl)
+
+	ctx := context.Background()
+
+	mockRepo.
+		EXPECT().
+		GetStreamVersion(ctx, "nothing-here").
+		Return(int64(-1), nil)
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamSince(ctx, "nothing-here", int64(0), 1000, "Event MessageType 1", "Event MessageType 2").
+		Return([]*repository.MessageEnvelope{}, nil)
+
+	entity, version, err := entityStore.FetchStream(ctx, "nothing-here")
+	if err != nil {
+		t.Fatalf("Error fetching entity: %s", err)
+	}
+
+	if version != -1 {
+		t.Errorf("Expected version -1 for an empty stream, got %d", version)
+	}
+
+	if entity != (mockDataStructure{}) {
+		t.Errorf("Expected the default state, got %+v", entity)
+	}
+}
+
+func TestEntityStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo, entityStore := getEntityStoreForTest(t, ctrl, EntityCacheSize(1))
+
+	ctx := context.Background()
+
+	mockRepo.
+		EXPECT().
+		GetStreamVersion(ctx, "first-1").
+		Return(int64(-1), nil).
+		Times(2)
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamSince(ctx, "first-1", int64(0), 1000, "Event MessageType 1", "Event MessageType 2").
+		Return([]*repository.MessageEnvelope{}, nil).
+		Times(2) // evicted by the fetch of the second stream, so read from scratch again
+	mockRepo.
+		EXPECT().
+		GetStreamVersion(ctx, "second-1").
+		Return(int64(-1), nil)
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamSince(ctx, "second-1", int64(0), 1000, "Event MessageType 1", "Event MessageType 2").
+		Return([]*repository.MessageEnvelope{}, nil)
+
+	for _, stream := range []string{"first-1", "second-1", "first-1"} {
+		if _, _, err := entityStore.FetchStream(ctx, stream); err != nil {
+			t.Fatalf("Error fetching entity: %s", err)
+		}
+	}
+}
+
+func TestEntityStoreUsesSnapshots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSnapshots := mock_gomessagestore.NewMockSnapshotStore(ctrl)
+	mockRepo, entityStore := getEntityStoreForTest(t, ctrl, EntitySnapshots(mockSnapshots), SnapshotInterval(2))
+
+	mockEventEnvs := getSampleEventsAsEnvelopes()
+	ctx := context.Background()
+	stream := mockEventEnvs[0].StreamName
+	snapshot := mockDataStructure{MockReducer2Called: true, MockReducer2CallCount: 1}
+
+	mockSnapshots.
+		EXPECT().
+		Get(ctx, stream).
+		Return(snapshot, mockEventEnvs[0].Version, nil)
+	mockRepo.
+		EXPECT().
+		GetStreamVersion(ctx, stream).
+		Return(mockEventEnvs[1].Version, nil)
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamSince(ctx, stream, mockEventEnvs[0].Version+1, 1000, "Event MessageType 1", "Event MessageType 2").
+		Return(mockEventEnvs[1:], nil)
+	mockSnapshots.
+		EXPECT().
+		Put(ctx, stream, mockDataStructure{
+			MockReducer1Called:    true,
+			MockReducer1CallCount: 1,
+			MockReducer2Called:    true,
+			MockReducer2CallCount: 1,
+		}, mockEventEnvs[1].Version)
+
+	_, version, err := entityStore.FetchStream(ctx, stream)
+	if err != nil {
+		t.Fatalf("Error fetching entity: %s", err)
+	}
+
+	if version != mockEventEnvs[1].Version {
+		t.Errorf("Wrong version\nExpected: %d\n     Got: %d\n", mockEventEnvs[1].Version, version)
+	}
+}
+
+func TestEntityStoreSnapshotFailureDoesNotFailFetch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSnapshots := mock_gomessagestore.NewMockSnapshotStore(ctrl)
+	mockRepo, entityStore := getEntityStoreForTest(t, ctrl, EntitySnapshots(mockSnapshots), SnapshotInterval(1))
+
+	mockEventEnvs := getSampleEventsAsEnvelopes()
+	ctx := context.Background()
+	stream := mockEventEnvs[0].StreamName
+
+	mockSnapshots.
+		EXPECT().
+		Get(ctx, stream).
+		Return(nil, int64(-1), nil)
+	mockRepo.
+		EXPECT().
+		GetStreamVersion(ctx, stream).
+		Return(mockEventEnvs[1].Version, nil)
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamSince(ctx, stream, int64(0), 1000, "Event MessageType 1", "Event MessageType 2").
+		Return(mockEventEnvs, nil)
+	mockSnapshots.
+		EXPECT().
+		Put(ctx, stream, gomock.Any(), mockEventEnvs[1].Version).
+		Return(errors.New("snapshot store is down"))
+
+	entity, version, err := entityStore.FetchStream(ctx, stream)
+	if err != nil {
+		t.Fatalf("Expected the fetch to succeed without a snapshot, got: %s", err)
+	}
+	if version != mockEventEnvs[1].Version || entity == nil {
+		t.Errorf("Wrong entity or version: %v, %d", entity, version)
+	}
+}
+
+func TestEntityStoreForgetsEntityWhenFetchFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo, entityStore := getEntityStoreForTest(t, ctrl)
+
+	mockEventEnvs := getSampleEventsAsEnvelopes()
+	ctx := context.Background()
+	stream := mockEventEnvs[0].StreamName
+	expectedErr := errors.New("bad things with db happened")
+
+	gomock.InOrder(
+		mockRepo.
+			EXPECT().
+			GetStreamVersion(ctx, stream).
+			Return(mockEventEnvs[0].Version, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStreamSince(ctx, stream, int64(0), 1000, "Event MessageType 1", "Event MessageType 2").
+			Return(mockEventEnvs[:1], nil),
+		mockRepo.
+			EXPECT().
+			GetStreamVersion(ctx, stream).
+			Return(mockEventEnvs[1].Version, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStreamSince(ctx, stream, mockEventEnvs[0].Version+1, 1000, "Event MessageType 1", "Event MessageType 2").
+			Return(nil, expectedErr),
+		// the cached entity is gone, so the stream is projected from the beginning again
+		mockRepo.
+			EXPECT().
+			GetStreamVersion(ctx, stream).
+			Return(mockEventEnvs[1].Version, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStreamSince(ctx, stream, int64(0), 1000, "Event MessageType 1", "Event MessageType 2").
+			Return(mockEventEnvs, nil),
+	)
+
+	if _, _, err := entityStore.FetchStream(ctx, stream); err != nil {
+		t.Fatalf("First fetch failed: %s", err)
+	}
+	if _, _, err := entityStore.FetchStream(ctx, stream); err != expectedErr {
+		t.Errorf("Failed to get expected error from FetchStream()\nExpected: %s\n and got: %v\n", expectedErr, err)
+	}
+
+	entity, version, err := entityStore.FetchStream(ctx, stream)
+	if err != nil || version != mockEventEnvs[1].Version {
+		t.Fatalf("Third fetch failed, version: %d err: %v", version, err)
+	}
+	state := entity.(mockDataStructure)
+	if state.MockReducer1CallCount != 1 || state.MockReducer2CallCount != 1 {
+		t.Errorf("Reducers were not applied exactly once each: %+v", state)
+	}
+}
+
+func TestEntityStoreCopiesEntityBeforeProjecting(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	writeAccountEvents(msgStore, "Deposited")
+
+	// counts deposits in place, so later fetches would change entities already handed out without a copy
+	projector, err := msgStore.CreateProjector(
+		StateFactory(func() interface{} { return new(int) }),
+		WithReducerFunc("Deposited", func(msg Message, previousState interface{}) (interface{}, error) {
+			count := previousState.(*int)
+			*count++
+			return count, nil
+		}),
+	)
+	panicIf(err)
+
+	entityStore, err := msgStore.CreateEntityStore(projector, EntityCopy(func(entity interface{}) interface{} {
+		count := *entity.(*int)
+		return &count
+	}))
+	panicIf(err)
+
+	first, _, err := entityStore.Fetch(ctx, "account", uuid1)
+	panicIf(err)
+	writeAccountEvents(msgStore, "Deposited")
+	second, _, err := entityStore.Fetch(ctx, "account", uuid1)
+	panicIf(err)
+
+	if *first.(*int) != 1 || *second.(*int) != 2 {
+		t.Errorf("Expected the first entity to be left alone at 1 and the second to be 2, got %d and %d", *first.(*int), *second.(*int))
+	}
+}
+
+func TestCreateEntityStoreValidatesOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	myMessageStore := NewMessageStoreFromRepository(mockRepo, logrus.New())
+
+	myprojector, err := myMessageStore.CreateProjector(
+		DefaultState(mockDataStructure{}),
+		WithReducer(new(mockReducer1)),
+	)
+	panicIf(err)
+
+	tests := []struct {
+		name        string
+		projector   Projector
+		opts        []EntityStoreOption
+		expectedErr error
+	}{{
+		name:        "a nil projector is rejected",
+		expectedErr: ErrEntityStoreNeedsProjector,
+	}, {
+		name:        "a cache size below one is rejected",
+		projector:   myprojector,
+		opts:        []EntityStoreOption{EntityCacheSize(0)},
+		expectedErr: ErrInvalidEntityCacheSize,
+	}, {
+		name:        "a snapshot interval below one is rejected",
+		projector:   myprojector,
+		opts:        []EntityStoreOption{SnapshotInterval(0)},
+		expectedErr: ErrInvalidSnapshotInterval,
+	}, {
+		name:      "a projector created by CreateProjector is accepted",
+		projector: myprojector,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := myMessageStore.CreateEntityStore(test.projector, test.opts...)
+			if err != test.expectedErr {
+				t.Errorf("Expected %v and got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
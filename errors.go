@@ -1,6 +1,9 @@
 package gomessagestore
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // The following are the different error messages that can be potentially returned.
 //
@@ -21,7 +24,7 @@ import "errors"
 //	ErrPositionVersionMissing                       |	./worker_getposition.go
 //	ErrSubscriberNeedsAtLeastOneMessageHandler      |	./subscriber.go
 //	ErrSubscriberCannotSubscribeToMultipleStreams   |	./subscriber_options.go
-//	ErrSubscriberCannotSubscribeToMultipleCategories|	./subscriber_options.go
+//	ErrSubscriberCannotSubscribeToMultipleCategories|	no uses
 //	ErrProjectorNeedsAtLeastOneReducer              |	./projector.go
 //	ErrSubscriberMessageHandlerEqualToNil           |	./subscriber.go
 //	ErrSubscriberMessageHandlersEqualToNil          |	./subscriber.go
@@ -49,6 +52,30 @@ import "errors"
 //	ErrDataIsNilPointer                             |	no uses
 //	ErrMissingGetOptions                            |	./get.go
 // ErrMessageNoEntityID                             | ./models.go
+//	ErrDefaultStateAndStateFactory                  |	./projector.go
+//	ErrStateFactoryReturnedNil                      |	./projector.go
+//	ErrUnhandledMessageType                         |	./projector.go
+//	ErrEntityStoreNeedsProjector                    |	./entity_store.go
+//	ErrInvalidEntityCacheSize                       |	./entity_store.go
+//	ErrInvalidSnapshotInterval                      |	./entity_store.go
+//	ErrGetAllCannotUseStreamOrCategory              |	./get.go
+//	ErrCategoryFiltersRequireAll                    |	./get.go | ./subscriber_options.go
+//	ErrSubscriberCannotUseAllWithStreamOrCategory   |	./subscriber_options.go
+//	ErrSubscriberCategorySubscribedTwice            |	./subscriber_options.go
+//	ErrRequestTimedOut                              |	./request.go
+//	ErrInvalidRequestTimeout                        |	./request.go
+//	ErrMissingReplyStream                           |	./request.go
+//	ErrInvalidReplyStream                           |	./request.go
+//	ErrMetadataNotAnObject                          |	./request.go | ./process_manager.go
+//	ErrProcessManagerNeedsCategory                  |	./process_manager.go
+//	ErrProcessManagerNeedsAtLeastOneHandler         |	./process_manager.go
+//	ErrMissingCorrelationID                         |	./process_manager.go
+//	ErrInvalidProcessState                          |	./process_manager.go
+//	ErrRepositoryDoesNotSupportTransactions         |	./messagestore.go
+//	ErrInvalidSubscriberPosition                    |	./subscriber_reset.go
+//	ErrInvalidReplayRange                           |	./subscriber_reset.go
+//	ErrInvalidMessageCount                          |	./get.go
+//	ErrDuplicateMessageID                           |	./write.go | ./process_manager.go
 var (
 	ErrInvalidOptionCombination                      = errors.New("Cannot have the current combination of options for Get()")
 	ErrSubscriberCannotUseBothStreamAndCategory      = errors.New("Subscriber function cannot use both Stream and Category")
@@ -92,4 +119,62 @@ var (
 	ErrMissingGetOptions                             = errors.New("Options are required for the Get command")
 	ErrExpectedVersionFailed                         = errors.New("Provided version does not match the expected version")
 	ErrMessageNoEntityID                             = errors.New("Message cannot be written without an EntityID")
+	ErrDefaultStateAndStateFactory                   = errors.New("Projector cannot have both a default state and a state factory")
+	ErrStateFactoryReturnedNil                       = errors.New("Projector state factory returned a nil state")
+	ErrUnhandledMessageType                          = errors.New("Projector in strict mode has no reducer for message type")
+	ErrEntityStoreNeedsProjector                     = errors.New("Entity store cannot be created without a projector")
+	ErrInvalidEntityCacheSize                        = errors.New("Entity store cache size must be at least 1")
+	ErrInvalidSnapshotInterval                       = errors.New("Entity store snapshot interval must be at least 1")
+	ErrGetAllCannotUseStreamOrCategory               = errors.New("Get messages from every category cannot use a Stream or Category")
+	ErrCategoryFiltersRequireAll                     = errors.New("Including or excluding categories is only possible when reading every category")
+	ErrSubscriberCannotUseAllWithStreamOrCategory    = errors.New("Subscriber to every category cannot also subscribe to a stream or category")
+	ErrSubscriberCategorySubscribedTwice             = errors.New("Subscriber cannot subscribe to the same category more than once")
+	ErrRequestTimedOut                               = errors.New("Timed out waiting for a reply to the command")
+	ErrInvalidRequestTimeout                         = errors.New("Request timeout must be greater than zero")
+	ErrMissingReplyStream                            = errors.New("Command has no reply stream in its metadata")
+	ErrInvalidReplyStream                            = errors.New("Reply stream must be an entity stream, made of a category and a UUID")
+	ErrMetadataNotAnObject                           = errors.New("Message metadata must be a JSON object")
+	ErrProcessManagerNeedsCategory                   = errors.New("Process manager needs a category for its state streams")
+	ErrProcessManagerNeedsAtLeastOneHandler          = errors.New("Process manager needs at least one handler upon creation")
+	ErrMissingCorrelationID                          = errors.New("Message could not be matched to a process")
+	ErrInvalidProcessState                           = errors.New("Process state stream holds a message that isn't an event")
+	ErrRepositoryDoesNotSupportTransactions          = errors.New("Message store's repository cannot be bound to a transaction")
+	ErrInvalidSubscriberPosition                     = errors.New("Subscriber position cannot be negative")
+	ErrInvalidReplayRange                            = errors.New("Replay range must start at or after zero and end at or after its start")
+	ErrInvalidMessageCount                           = errors.New("Number of messages must be greater than zero")
+	ErrDuplicateMessageID                            = errors.New("A message with this ID has already been written")
 )
+
+// ProjectionError is returned when a projector can't apply a message, and points at the message that broke the projection
+type ProjectionError struct {
+	StreamName     string // the stream the message was written to (blank if the message couldn't be converted to an envelope)
+	MessageType    string
+	Version        int64
+	GlobalPosition int64
+	Err            error // the error from the reducer, or ErrUnhandledMessageType
+}
+
+// newProjectionError wraps an error with the details of the message that caused it
+func newProjectionError(msg Message, err error) *ProjectionError {
+	projErr := &ProjectionError{
+		MessageType:    msg.Type(),
+		Version:        msg.Version(),
+		GlobalPosition: msg.Position(),
+		Err:            err,
+	}
+
+	if msgEnv, envErr := msg.ToEnvelope(); envErr == nil {
+		projErr.StreamName = msgEnv.StreamName
+	}
+
+	return projErr
+}
+
+func (e *ProjectionError) Error() string {
+	return fmt.Sprintf("projecting %s message at version %d of stream %s (global position %d): %s", e.MessageType, e.Version, e.StreamName, e.GlobalPosition, e.Err)
+}
+
+// Unwrap gives access to the underlying error
+func (e *ProjectionError) Unwrap() error {
+	return e.Err
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/blackhatbrigade/gomessagestore/internal/jsonenvelope"
 	"github.com/blackhatbrigade/gomessagestore/repository"
 	"github.com/blackhatbrigade/gomessagestore/uuid"
 )
@@ -103,8 +104,8 @@ func (e Event) MarshalJSON() ([]byte, error) {
 		"messageType":    e.MessageType,
 		"messageVersion": e.MessageVersion,
 		"globalPosition": e.GlobalPosition,
-		"data":           json.RawMessage(string(e.Data)),
-		"metadata":       json.RawMessage(string(e.Metadata)),
+		"data":           jsonenvelope.Raw(e.Data),
+		"metadata":       jsonenvelope.Raw(e.Metadata),
 		"time":           e.Time,
 	}
 
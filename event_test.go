@@ -1,6 +1,7 @@
 package gomessagestore_test
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -98,3 +99,21 @@ func TestEventToEnvelope(t *testing.T) {
 		})
 	}
 }
+
+func TestEventMarshalJSONWithoutMetadata(t *testing.T) {
+	evt := NewEvent(uuid1, uuid2, "test cat", "test type", []byte(`{"Field1":"a"}`), nil)
+
+	data, err := json.Marshal(evt)
+	if err != nil {
+		t.Fatalf("Failed to marshal an event without metadata: %s", err)
+	}
+
+	var holder map[string]interface{}
+	panicIf(json.Unmarshal(data, &holder))
+	if holder["metadata"] != nil {
+		t.Errorf("Expected null metadata, got %v", holder["metadata"])
+	}
+	if holder["data"].(map[string]interface{})["Field1"] != "a" {
+		t.Errorf("Data was not embedded as JSON: %s", data)
+	}
+}
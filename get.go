@@ -3,7 +3,9 @@ package gomessagestore
 import (
 	"context"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/blackhatbrigade/gomessagestore/repository"
 	"github.com/blackhatbrigade/gomessagestore/uuid"
@@ -11,14 +13,22 @@ import (
 )
 
 type getOpts struct {
-	stream        *string            // when set, only messages from the specified stream are retrieved
-	category      *string            // when set, only messages from the specified category are retrieved
-	sincePosition bool               // when set to true, only messages that occured after the specified position (since) for the category are retrieved; invalid for use with streams
-	sinceVersion  bool               // when set to true, only messages that occured since teh specified version (since) for the stream are retrieved; invalid for use with categories
-	since         *int64             // the position or version after which messages will be retrieved
-	converters    []MessageConverter // convert non-command/event messages
-	batchsize     int                // the number of messages to retrieve each round
-	last          bool               // when set to true, retrieves the last message in the specified stream; invalid if stream is unspecified or since is not nil
+	stream        *string                   // when set, only messages from the specified stream are retrieved
+	category      *string                   // when set, only messages from the specified category are retrieved
+	sincePosition bool                      // when set to true, only messages that occured after the specified position (since) for the category are retrieved; invalid for use with streams
+	sinceVersion  bool                      // when set to true, only messages that occured since teh specified version (since) for the stream are retrieved; invalid for use with categories
+	since         *int64                    // the position or version after which messages will be retrieved
+	converters    []MessageConverter        // convert non-command/event messages
+	batchsize     int                       // the number of messages to retrieve each round
+	last          bool                      // when set to true, retrieves the last message in the specified stream; invalid if stream is unspecified or since is not nil
+	all           bool                      // when set to true, messages from every category are retrieved in global position order; invalid with a stream or category
+	filter        repository.CategoryFilter // the categories to include or exclude when all is set
+	sinceTime     *time.Time                // when set, only messages written at or after this time are retrieved; resolved to since before reading
+	untilTime     *time.Time                // when set, only messages written before this time are retrieved; resolved to until before reading
+	until         *int64                    // the position or version before which messages will be retrieved
+	backward      bool                      // when set to true, messages are retrieved newest first, from the end or at or before since; invalid with All() and Last()
+	lastN         int                       // when set, only the most recent lastN messages are retrieved, newest first
+	types         []string                  // when set, only messages of these types are retrieved; invalid with Last() and Backward()/LastN()
 }
 
 // GetOption provide optional arguments to the Get function
@@ -30,6 +40,13 @@ type getOpts struct {
 // Last() and SincePosition()/SinceVersion() are both called
 // SincePosition() and eventStream()/CommandStream() are both called
 // SinceVersion() and eventStream()/CommandStream() are both called
+// All() and any of EventStream()/CommandStream()/Category() are called
+// IncludeCategories()/ExcludeCategories() are called without All()
+// SinceTime() and SincePosition()/SinceVersion() are both called
+// Last() and SinceTime()/UntilTime() are both called
+// Backward()/LastN() and any of Last()/All()/SinceTime()/UntilTime() are called
+// LastN() is called more than once
+// Types() and any of Last()/Backward()/LastN() are called
 type GetOption func(g *getOpts) error
 
 // checkGetOptions returns the supplied options
@@ -60,6 +77,12 @@ func (ms *msgStore) Get(ctx context.Context, opts ...GetOption) ([]Message, erro
 		return nil, err
 	}
 
+	if err := ms.resolveTimes(ctx, getOptions); err != nil {
+		logrus.WithError(err).Error("Get: Error resolving times to positions")
+
+		return nil, err
+	}
+
 	// Uses getOptions to determine what call to issue to retrieve correct messages
 	msgEnvelopes, err := ms.callCorrectRepositoryGetFunction(ctx, getOptions)
 
@@ -69,12 +92,97 @@ func (ms *msgStore) Get(ctx context.Context, opts ...GetOption) ([]Message, erro
 		return nil, err
 	}
 
-	return MsgEnvelopesToMessages(msgEnvelopes, getOptions.converters...), nil
+	return MsgEnvelopesToMessages(getOptions.trimUntil(msgEnvelopes), getOptions.converters...), nil
+}
+
+// PositionAtTime gets the position Get starts from with SinceTime(): the version of the first message in a stream written at or after the time,
+// or the global position of the first message written at or after it otherwise. When there is no such message, the next one's is returned.
+// The options pick what's read, like they do for Get.
+func (ms *msgStore) PositionAtTime(ctx context.Context, t time.Time, opts ...GetOption) (int64, error) {
+	getOptions, err := checkGetOptions(opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := validateGetParams(getOptions); err != nil {
+		return 0, err
+	}
+
+	return ms.positionAtTime(ctx, getOptions, t)
+}
+
+// positionAtTime resolves a time to a version for streams, or a global position for categories and All()
+func (ms *msgStore) positionAtTime(ctx context.Context, getOptions *getOpts, t time.Time) (int64, error) {
+	if getOptions.stream != nil {
+		return ms.repo.GetStreamVersionAtTime(ctx, *getOptions.stream, t)
+	}
+
+	// global positions go up with time, so the first message in a category at or after the time is at or after this
+	return ms.repo.GetGlobalPositionAtTime(ctx, t)
+}
+
+// resolveTimes turns SinceTime() and UntilTime() into positions, so the repository reads by position as usual
+func (ms *msgStore) resolveTimes(ctx context.Context, getOptions *getOpts) error {
+	if getOptions.sinceTime != nil {
+		since, err := ms.positionAtTime(ctx, getOptions, *getOptions.sinceTime)
+		if err != nil {
+			return err
+		}
+		getOptions.since = &since
+	}
+
+	if getOptions.untilTime != nil {
+		until, err := ms.positionAtTime(ctx, getOptions, *getOptions.untilTime)
+		if err != nil {
+			return err
+		}
+		getOptions.until = &until
+	}
+
+	return nil
+}
+
+// trimUntil drops the messages at or after the position UntilTime() was resolved to
+func (getOptions *getOpts) trimUntil(msgEnvelopes []*repository.MessageEnvelope) []*repository.MessageEnvelope {
+	if getOptions.until == nil {
+		return msgEnvelopes
+	}
+
+	for i, msgEnvelope := range msgEnvelopes {
+		position := msgEnvelope.GlobalPosition
+		if getOptions.stream != nil {
+			position = msgEnvelope.Version
+		}
+		if position >= *getOptions.until {
+			return msgEnvelopes[:i]
+		}
+	}
+
+	return msgEnvelopes
 }
 
 // Ensure that only proper combinations of getOpts are provided.
 // See getOpts for more info regarding these checks
 func validateGetParams(getOptions *getOpts) error {
+	if getOptions.sinceTime != nil && getOptions.since != nil {
+		return ErrInvalidOptionCombination // SinceTime is resolved to a position or version itself
+	}
+	if getOptions.last && (getOptions.sinceTime != nil || getOptions.untilTime != nil) {
+		return ErrInvalidOptionCombination
+	}
+	if getOptions.backward && (getOptions.last || getOptions.sinceTime != nil || getOptions.untilTime != nil) {
+		return ErrInvalidOptionCombination // Last() already reads backward, and times only resolve to where forward reads start
+	}
+	if len(getOptions.types) > 0 && (getOptions.last || getOptions.backward) {
+		return ErrInvalidOptionCombination // only forward reads filter by type
+	}
+	if getOptions.all {
+		return validateGetAllParams(getOptions)
+	}
+	if len(getOptions.filter.Include) > 0 || len(getOptions.filter.Exclude) > 0 {
+		return ErrCategoryFiltersRequireAll
+	}
+
 	if getOptions.stream != nil && getOptions.category != nil {
 		return ErrGetMessagesCannotUseBothStreamAndCategory
 	} else if getOptions.stream == nil && getOptions.category == nil {
@@ -96,13 +204,58 @@ func validateGetParams(getOptions *getOpts) error {
 	return nil
 }
 
+// validateGetAllParams ensures nothing that only makes sense for a single stream or category is combined with All()
+func validateGetAllParams(getOptions *getOpts) error {
+	if getOptions.stream != nil || getOptions.category != nil {
+		return ErrGetAllCannotUseStreamOrCategory
+	}
+	if getOptions.last || getOptions.sinceVersion {
+		return ErrInvalidOptionCombination // need to use SincePosition with All
+	}
+	if getOptions.backward {
+		return ErrInvalidOptionCombination // only streams and categories can be read backward
+	}
+
+	return nil
+}
+
 // callCorrectRepositoryGetFunction uses the getOptions to determine which function should be called to retrieve the correct messages.
 func (ms *msgStore) callCorrectRepositoryGetFunction(ctx context.Context, getOptions *getOpts) (msgEnvelopes []*repository.MessageEnvelope, err error) {
+	if getOptions.all {
+		var since int64
+		if getOptions.since != nil {
+			since = *getOptions.since
+		}
+
+		return ms.repo.GetAllMessagesSince(ctx, since, getOptions.batchsize, getOptions.filter, getOptions.types...)
+	}
+
+	if getOptions.backward {
+		batchSize := getOptions.batchsize
+		if getOptions.lastN > 0 {
+			batchSize = getOptions.lastN
+		}
+		from := int64(math.MaxInt64) // from the newest message
+		if getOptions.since != nil {
+			from = *getOptions.since
+		}
+
+		if getOptions.stream != nil {
+			return ms.repo.GetAllMessagesInStreamBackward(ctx, *getOptions.stream, from, batchSize)
+		}
+		return ms.repo.GetAllMessagesInCategoryBackward(ctx, *getOptions.category, from, batchSize)
+	}
+
+	if getOptions.since == nil && len(getOptions.types) > 0 {
+		since := int64(0) // only the since reads filter by type, and reading since the start reads everything
+		getOptions.since = &since
+	}
+
 	if getOptions.since != nil {
 		if getOptions.stream != nil {
-			msgEnvelopes, err = ms.repo.GetAllMessagesInStreamSince(ctx, *getOptions.stream, *getOptions.since, getOptions.batchsize)
+			msgEnvelopes, err = ms.repo.GetAllMessagesInStreamSince(ctx, *getOptions.stream, *getOptions.since, getOptions.batchsize, getOptions.types...)
 		} else {
-			msgEnvelopes, err = ms.repo.GetAllMessagesInCategorySince(ctx, *getOptions.category, *getOptions.since, getOptions.batchsize)
+			msgEnvelopes, err = ms.repo.GetAllMessagesInCategorySince(ctx, *getOptions.category, *getOptions.since, getOptions.batchsize, getOptions.types...)
 		}
 	} else {
 		if getOptions.last {
@@ -183,6 +336,40 @@ func Category(category string) GetOption {
 	}
 }
 
+// All allows for getting messages from every category, in global position order
+func All() GetOption {
+	return func(g *getOpts) error {
+		g.all = true
+		return nil
+	}
+}
+
+// IncludeCategories limits All() to messages in the categories given
+func IncludeCategories(categories ...string) GetOption {
+	return func(g *getOpts) error {
+		for _, category := range categories {
+			if strings.Contains(category, "-") {
+				return ErrInvalidMessageCategory
+			}
+		}
+		g.filter.Include = append(g.filter.Include, categories...)
+		return nil
+	}
+}
+
+// ExcludeCategories stops All() from getting messages in the categories given
+func ExcludeCategories(categories ...string) GetOption {
+	return func(g *getOpts) error {
+		for _, category := range categories {
+			if strings.Contains(category, "-") {
+				return ErrInvalidMessageCategory
+			}
+		}
+		g.filter.Exclude = append(g.filter.Exclude, categories...)
+		return nil
+	}
+}
+
 // CommandCategory allows for getting messages by category
 func CommandCategory(category string) GetOption {
 	return func(g *getOpts) error {
@@ -248,6 +435,60 @@ func SinceVersion(version int64) GetOption {
 	}
 }
 
+//Backward allows for getting messages newest first; with SincePosition()/SinceVersion() it pages back from that position, which is included
+func Backward() GetOption {
+	return func(g *getOpts) error {
+		g.backward = true
+		return nil
+	}
+}
+
+//LastN allows for getting the most recent n messages of a stream or category, newest first
+func LastN(n int) GetOption {
+	return func(g *getOpts) error {
+		if n < 1 {
+			return ErrInvalidMessageCount
+		}
+		if g.lastN != 0 {
+			return ErrInvalidOptionCombination
+		}
+		g.lastN = n
+		g.backward = true
+		return nil
+	}
+}
+
+//SinceTime allows for getting only messages written at or after a time; works with streams, categories and All()
+func SinceTime(t time.Time) GetOption {
+	return func(g *getOpts) error {
+		if g.sinceTime != nil {
+			return ErrInvalidOptionCombination
+		}
+		g.sinceTime = &t
+		return nil
+	}
+}
+
+//UntilTime allows for getting only messages written before a time; works with streams, categories and All()
+func UntilTime(t time.Time) GetOption {
+	return func(g *getOpts) error {
+		if g.untilTime != nil {
+			return ErrInvalidOptionCombination
+		}
+		g.untilTime = &t
+		return nil
+	}
+}
+
+//Types allows for getting only messages of the types given, filtered by the repository so the rest are never read; BatchSize()
+//counts only the messages of these types. Works with streams, categories and All()
+func Types(types ...string) GetOption {
+	return func(g *getOpts) error {
+		g.types = append(g.types, types...)
+		return nil
+	}
+}
+
 //Converter allows for automatic converting of non-Command/Event type messages
 func Converter(converter MessageConverter) GetOption {
 	return func(g *getOpts) error {
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"testing"
+	"time"
 
 	. "github.com/blackhatbrigade/gomessagestore"
 	"github.com/blackhatbrigade/gomessagestore/repository"
@@ -229,6 +231,242 @@ func TestGetWithCategoryAndSince(t *testing.T) {
 	}
 }
 
+func TestGetAllWithCategoryFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	msg := getSampleEvent()
+	ctx := context.Background()
+
+	logrusLogger := logrus.New()
+	logrusLogger.Out = ioutil.Discard
+	msgEnv := getSampleEventAsEnvelope()
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesSince(ctx, int64(42), 1000, repository.CategoryFilter{Include: []string{msg.StreamCategory}, Exclude: []string{"audit", "metrics"}}).
+		Return([]*repository.MessageEnvelope{msgEnv}, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+	msgs, err := msgStore.Get(
+		ctx,
+		All(),
+		SincePosition(42),
+		IncludeCategories(msg.StreamCategory),
+		ExcludeCategories("audit", "metrics"),
+	)
+
+	if err != nil {
+		t.Error("An error has ocurred while getting messages from message store")
+	}
+	if len(msgs) != 1 {
+		t.Error("Incorrect number of messages returned")
+	} else {
+		assertMessageMatchesEvent(t, msgs[0], msg)
+	}
+}
+
+func TestGetAllWithoutSinceStartsAtTheBeginning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	logrusLogger := logrus.New()
+	logrusLogger.Out = ioutil.Discard
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesSince(ctx, int64(0), 1000, repository.CategoryFilter{}).
+		Return(nil, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+	msgs, err := msgStore.Get(ctx, All())
+
+	if err != nil {
+		t.Error("An error has ocurred while getting messages from message store")
+	}
+	if len(msgs) != 0 {
+		t.Error("Incorrect number of messages returned")
+	}
+}
+
+func TestGetWithEventStreamAndTimes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+	since := time.Unix(100, 0)
+	until := time.Unix(200, 0)
+	stream := "some_type-" + uuid1.String()
+
+	logrusLogger := logrus.New()
+	logrusLogger.Out = ioutil.Discard
+
+	envelopes := getLotsOfSampleEventsAsEnvelopes(4, 2)
+	mockRepo.
+		EXPECT().
+		GetStreamVersionAtTime(ctx, stream, since).
+		Return(int64(2), nil)
+	mockRepo.
+		EXPECT().
+		GetStreamVersionAtTime(ctx, stream, until).
+		Return(envelopes[2].Version, nil)
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamSince(ctx, stream, int64(2), 1000).
+		Return(envelopes, nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+	msgs, err := msgStore.Get(ctx, EventStream("some_type", uuid1), SinceTime(since), UntilTime(until))
+
+	if err != nil {
+		t.Errorf("An error has ocurred while getting messages from message store: %s", err)
+	}
+	if len(msgs) != 2 {
+		t.Errorf("Expected the messages before UntilTime, got %d messages", len(msgs))
+	}
+}
+
+func TestGetWithCategoryAndSinceTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+	since := time.Unix(100, 0)
+
+	logrusLogger := logrus.New()
+	logrusLogger.Out = ioutil.Discard
+
+	mockRepo.
+		EXPECT().
+		GetGlobalPositionAtTime(ctx, since).
+		Return(int64(42), nil)
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInCategorySince(ctx, "some_type", int64(42), 1000).
+		Return(getSampleEventsAsEnvelopes(), nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+	msgs, err := msgStore.Get(ctx, Category("some_type"), SinceTime(since))
+
+	if err != nil {
+		t.Errorf("An error has ocurred while getting messages from message store: %s", err)
+	}
+	if len(msgs) != len(getSampleEventsAsEnvelopes()) {
+		t.Error("Incorrect number of messages returned")
+	}
+}
+
+func TestGetWithEventStreamAndLastN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	logrusLogger := logrus.New()
+	logrusLogger.Out = ioutil.Discard
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamBackward(ctx, "some_type-"+uuid1.String(), int64(math.MaxInt64), 2).
+		Return(getSampleEventsAsEnvelopes(), nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+	msgs, err := msgStore.Get(ctx, EventStream("some_type", uuid1), LastN(2))
+
+	if err != nil {
+		t.Errorf("An error has ocurred while getting messages from message store: %s", err)
+	}
+	if len(msgs) != len(getSampleEventsAsEnvelopes()) {
+		t.Error("Incorrect number of messages returned")
+	}
+}
+
+func TestGetWithCategoryBackwardSincePosition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	logrusLogger := logrus.New()
+	logrusLogger.Out = ioutil.Discard
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInCategoryBackward(ctx, "some_type", int64(42), 10).
+		Return(getSampleEventsAsEnvelopes(), nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+	msgs, err := msgStore.Get(ctx, Category("some_type"), Backward(), SincePosition(42), BatchSize(10))
+
+	if err != nil {
+		t.Errorf("An error has ocurred while getting messages from message store: %s", err)
+	}
+	if len(msgs) != len(getSampleEventsAsEnvelopes()) {
+		t.Error("Incorrect number of messages returned")
+	}
+}
+
+func TestGetWithEventStreamAndTypes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	logrusLogger := logrus.New()
+	logrusLogger.Out = ioutil.Discard
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamSince(ctx, "some_type-"+uuid1.String(), int64(0), 1000, "Opened", "Closed").
+		Return(getSampleEventsAsEnvelopes(), nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+	msgs, err := msgStore.Get(ctx, EventStream("some_type", uuid1), Types("Opened"), Types("Closed"))
+
+	if err != nil {
+		t.Errorf("An error has ocurred while getting messages from message store: %s", err)
+	}
+	if len(msgs) != len(getSampleEventsAsEnvelopes()) {
+		t.Error("Incorrect number of messages returned")
+	}
+}
+
+func TestGetWithAllSincePositionAndTypes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	logrusLogger := logrus.New()
+	logrusLogger.Out = ioutil.Discard
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesSince(ctx, int64(42), 10, repository.CategoryFilter{Include: []string{"some_type"}}, "Opened").
+		Return(getSampleEventsAsEnvelopes(), nil)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+	msgs, err := msgStore.Get(ctx, All(), IncludeCategories("some_type"), SincePosition(42), BatchSize(10), Types("Opened"))
+
+	if err != nil {
+		t.Errorf("An error has ocurred while getting messages from message store: %s", err)
+	}
+	if len(msgs) != len(getSampleEventsAsEnvelopes()) {
+		t.Error("Incorrect number of messages returned")
+	}
+}
+
 func TestGetMessagesCannotUseBothStreamAndCategory(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -547,6 +785,92 @@ func TestOptionErrors(t *testing.T) {
 			Last(),
 			CommandStream("yayaya"),
 		},
+	}, {
+		name:          "SinceTime and SincePosition are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			SinceTime(time.Unix(5, 0)),
+			SincePosition(5),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "SinceTime is set twice",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			SinceTime(time.Unix(5, 0)),
+			SinceTime(time.Unix(10, 0)),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "UntilTime and Last are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			UntilTime(time.Unix(5, 0)),
+			Last(),
+			CommandStream("yayaya"),
+		},
+	}, {
+		name:          "Backward and Last are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Backward(),
+			Last(),
+			CommandStream("yayaya"),
+		},
+	}, {
+		name:          "LastN and All are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			LastN(5),
+			All(),
+		},
+	}, {
+		name:          "Backward and SinceTime are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Backward(),
+			SinceTime(time.Unix(5, 0)),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "LastN is set twice",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			LastN(5),
+			LastN(10),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "LastN needs at least one message",
+		expectedError: ErrInvalidMessageCount,
+		opts: []GetOption{
+			LastN(0),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "Backward with SincePosition on a stream",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Backward(),
+			SincePosition(5),
+			CommandStream("yayaya"),
+		},
+	}, {
+		name:          "Types and Last are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Types("Opened"),
+			Last(),
+			CommandStream("yayaya"),
+		},
+	}, {
+		name:          "Types and LastN are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			Types("Opened"),
+			LastN(5),
+			Category("yayaya"),
+		},
 	}, {
 		name:          "SinceVersion and Category are both set",
 		expectedError: ErrInvalidOptionCombination,
@@ -613,6 +937,48 @@ func TestOptionErrors(t *testing.T) {
 		opts: []GetOption{
 			PositionStream("hyphen-hyphen"),
 		},
+	}, {
+		name:          "All and Category are both set",
+		expectedError: ErrGetAllCannotUseStreamOrCategory,
+		opts: []GetOption{
+			All(),
+			Category("yayaya"),
+		},
+	}, {
+		name:          "All and Command Stream are both set",
+		expectedError: ErrGetAllCannotUseStreamOrCategory,
+		opts: []GetOption{
+			CommandStream("yayaya"),
+			All(),
+		},
+	}, {
+		name:          "All and Last are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			All(),
+			Last(),
+		},
+	}, {
+		name:          "All and SinceVersion are both set",
+		expectedError: ErrInvalidOptionCombination,
+		opts: []GetOption{
+			All(),
+			SinceVersion(5),
+		},
+	}, {
+		name:          "IncludeCategories without All",
+		expectedError: ErrCategoryFiltersRequireAll,
+		opts: []GetOption{
+			Category("yayaya"),
+			IncludeCategories("yayaya"),
+		},
+	}, {
+		name:          "ExcludeCategories cannot contain a hyphen",
+		expectedError: ErrInvalidMessageCategory,
+		opts: []GetOption{
+			All(),
+			ExcludeCategories("hyphen-hyphen"),
+		},
 	}}
 
 	for _, test := range tests {
@@ -1,11 +1,13 @@
 module github.com/blackhatbrigade/gomessagestore
 
-go 1.12
+go 1.13
 
 require (
 	github.com/DATA-DOG/go-sqlmock v1.3.3
 	github.com/golang/mock v1.4.1
 	github.com/jmoiron/sqlx v1.2.0
+	github.com/lib/pq v1.2.0
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/sirupsen/logrus v1.4.2
 	github.com/stretchr/testify v1.2.2
 )
@@ -0,0 +1,193 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	gms "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/sirupsen/logrus"
+)
+
+//Page is a page of messages read over HTTP
+type Page struct {
+	Messages []gms.Message
+	Next     string // the token that reads the page after this one
+}
+
+//ClientOption configures a client when it's created
+type ClientOption func(c *Client)
+
+//HTTPClient sets the client requests are made with (default http.DefaultClient)
+func HTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+//ReadOption changes what's read in a page
+type ReadOption func(values url.Values)
+
+//Limit sets the most messages in the page, up to the 1000 the handler allows
+func Limit(limit int) ReadOption {
+	return func(values url.Values) {
+		values.Set("limit", strconv.Itoa(limit))
+	}
+}
+
+//Wait waits for new messages when there are none yet, returning an empty page if none are written in time
+func Wait(wait time.Duration) ReadOption {
+	return func(values url.Values) {
+		values.Set("wait", wait.String())
+	}
+}
+
+//Types only reads messages of the types
+func Types(types ...string) ReadOption {
+	return func(values url.Values) {
+		for _, msgType := range types {
+			values.Add("type", msgType)
+		}
+	}
+}
+
+//Client reads from a handler created with NewHandler
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	ms         gms.MessageStore
+}
+
+//NewClient creates a client for the handler at the base URL. The handler only serves streams and categories read forward in pages,
+//so that's all the client reads: Get with Last(), LastN(), Backward(), All(), SinceTime() or UntilTime() returns ErrNotSupported.
+//Use NewMessageStore against a server created with NewServer to read everything.
+func NewClient(baseURL string, log logrus.FieldLogger, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+
+	for _, option := range opts {
+		option(c)
+	}
+
+	c.ms = gms.NewMessageStoreFromRepository(&remoteRepository{client: c}, log)
+
+	return c
+}
+
+//ReadStream reads the page of a stream the token starts at; an empty token starts at the beginning
+func (c *Client) ReadStream(ctx context.Context, streamName string, token string, opts ...ReadOption) (*Page, error) {
+	return c.readPage(ctx, "/streams/", streamName, token, opts...)
+}
+
+//ReadCategory reads the page of a category the token starts at; an empty token starts at the beginning
+func (c *Client) ReadCategory(ctx context.Context, category string, token string, opts ...ReadOption) (*Page, error) {
+	return c.readPage(ctx, "/categories/", category, token, opts...)
+}
+
+//Get reads messages like MessageStore.Get does. Streams and categories can be read forward, from the beginning or since
+//a version or global position, with or without Types(), up to 1000 messages at a time; anything else returns ErrNotSupported
+func (c *Client) Get(ctx context.Context, opts ...gms.GetOption) ([]gms.Message, error) {
+	return c.ms.Get(ctx, opts...)
+}
+
+// readPage reads a page, converting its messages
+func (c *Client) readPage(ctx context.Context, prefix, name, token string, opts ...ReadOption) (*Page, error) {
+	p, err := c.read(ctx, prefix, name, token, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	msgEnvelopes := make([]*repository.MessageEnvelope, len(p.Messages))
+	for i, msg := range p.Messages {
+		msgEnvelopes[i] = msg.ToEnvelope()
+	}
+
+	return &Page{
+		Messages: gms.MsgEnvelopesToMessages(msgEnvelopes),
+		Next:     p.Next,
+	}, nil
+}
+
+// read reads a page as it was sent
+func (c *Client) read(ctx context.Context, prefix, name, token string, opts ...ReadOption) (*page, error) {
+	values := url.Values{}
+	if token != "" {
+		values.Set("token", token)
+	}
+	for _, option := range opts {
+		option(values)
+	}
+
+	p := &page{}
+	if err := c.get(ctx, prefix+url.PathEscape(name), values, p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// get makes a GET request for the path, decoding the response into body
+func (c *Client) get(ctx context.Context, path string, values url.Values, body interface{}) error {
+	u := c.baseURL + path
+	if len(values) > 0 {
+		u += "?" + values.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.do(req.WithContext(ctx), body)
+}
+
+// post makes a POST request to the path, sending reqBody as JSON
+func (c *Client) post(ctx context.Context, path string, reqBody interface{}) error {
+	encoded, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+path, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.do(req.WithContext(ctx), nil)
+}
+
+// do makes a request, decoding the response into body when there is one; errors the handler returned come back as the error they were
+func (c *Client) do(req *http.Request, body interface{}) error {
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		var f failure
+		if err := json.NewDecoder(res.Body).Decode(&f); err != nil || f.Error == "" {
+			return fmt.Errorf("%s %s: %s", req.Method, req.URL.Path, res.Status)
+		}
+		if res.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Path, res.Status, f.Error)
+		}
+
+		return repository.Error(f.Error) // so it's equal to the error that was returned, like repository.ErrInvalidStreamName
+	}
+	if body == nil {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(body)
+}
@@ -0,0 +1,163 @@
+package http_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gms "github.com/blackhatbrigade/gomessagestore"
+	. "github.com/blackhatbrigade/gomessagestore/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// typesOf gets the type of every message
+func typesOf(msgs []gms.Message) []string {
+	types := []string{}
+	for _, msg := range msgs {
+		types = append(types, msg.Type())
+	}
+
+	return types
+}
+
+func TestClientPagesThroughCategory(t *testing.T) {
+	server, repo := newServer(t)
+	defer server.Close()
+	writeMessages(t, repo, "account-1", "Opened", "Deposited")
+	writeMessages(t, repo, "account-2", "Opened")
+	client := newClient(server)
+	ctx := context.Background()
+
+	first, err := client.ReadCategory(ctx, "account", "", Limit(2))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Opened", "Deposited"}, typesOf(first.Messages))
+
+	second, err := client.ReadCategory(ctx, "account", first.Next, Limit(2))
+	assert.Nil(t, err)
+	if assert.Len(t, second.Messages, 1) {
+		assert.Equal(t, int64(3), second.Messages[0].Position())
+	}
+
+	third, err := client.ReadCategory(ctx, "account", second.Next, Limit(2))
+	assert.Nil(t, err)
+	assert.Empty(t, third.Messages)
+	assert.Equal(t, second.Next, third.Next, "an empty page should start where new messages will show up")
+}
+
+func TestClientReadsStreamOfTypes(t *testing.T) {
+	server, repo := newServer(t)
+	defer server.Close()
+	writeMessages(t, repo, "account-1", "Opened", "Deposited", "Withdrawn", "Deposited")
+	client := newClient(server)
+
+	page, err := client.ReadStream(context.Background(), "account-1", "", Types("Deposited"))
+	assert.Nil(t, err)
+	if assert.Len(t, page.Messages, 2) {
+		assert.Equal(t, int64(1), page.Messages[0].Version())
+		assert.Equal(t, int64(3), page.Messages[1].Version())
+	}
+}
+
+func TestClientWaitsForNewMessages(t *testing.T) {
+	server, repo := newServer(t, PollInterval(10*time.Millisecond))
+	defer server.Close()
+	writeMessages(t, repo, "account-1", "Opened")
+	client := newClient(server)
+	ctx := context.Background()
+
+	caughtUp, err := client.ReadStream(ctx, "account-1", "")
+	assert.Nil(t, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		writeMessages(t, repo, "account-1", "Deposited")
+	}()
+
+	page, err := client.ReadStream(ctx, "account-1", caughtUp.Next, Wait(5*time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Deposited"}, typesOf(page.Messages))
+}
+
+func TestClientWaitReturnsEmptyPageWhenNothingIsWritten(t *testing.T) {
+	server, _ := newServer(t, PollInterval(10*time.Millisecond))
+	defer server.Close()
+	client := newClient(server)
+
+	start := time.Now()
+	page, err := client.ReadCategory(context.Background(), "account", "", Wait(50*time.Millisecond))
+	assert.Nil(t, err)
+	assert.Empty(t, page.Messages)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond, "should have waited before returning")
+}
+
+func TestClientReturnsHandlerErrors(t *testing.T) {
+	server, _ := newServer(t)
+	defer server.Close()
+	client := newClient(server)
+
+	_, err := client.ReadStream(context.Background(), "account-1", "nonsense!")
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestClientGet(t *testing.T) {
+	server, repo := newServer(t)
+	defer server.Close()
+	writeMessages(t, repo, "account-1", "Opened", "Deposited")
+	writeMessages(t, repo, "account-2", "Opened", "Withdrawn")
+	client := newClient(server)
+	ctx := context.Background()
+
+	msgs, err := client.Get(ctx, gms.Category("account"), gms.SincePosition(2))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Deposited", "Opened", "Withdrawn"}, typesOf(msgs))
+
+	msgs, err = client.Get(ctx, gms.GenericStream("account-2"), gms.Types("Withdrawn"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Withdrawn"}, typesOf(msgs))
+}
+
+func TestClientGetRejectsWhatTheHandlerCannotRead(t *testing.T) {
+	server, repo := newServer(t)
+	defer server.Close()
+	writeMessages(t, repo, "account-1", "Opened", "Deposited")
+	client := newClient(server)
+
+	tests := []struct {
+		name string
+		opts []gms.GetOption
+	}{{
+		name: "the last message in a stream",
+		opts: []gms.GetOption{gms.GenericStream("account-1"), gms.Last()},
+	}, {
+		name: "the last messages in a stream",
+		opts: []gms.GetOption{gms.GenericStream("account-1"), gms.LastN(2)},
+	}, {
+		name: "a stream backward",
+		opts: []gms.GetOption{gms.GenericStream("account-1"), gms.Backward()},
+	}, {
+		name: "a category backward",
+		opts: []gms.GetOption{gms.Category("account"), gms.SincePosition(2), gms.Backward()},
+	}, {
+		name: "every category",
+		opts: []gms.GetOption{gms.All()},
+	}, {
+		name: "some categories",
+		opts: []gms.GetOption{gms.All(), gms.IncludeCategories("account")},
+	}, {
+		name: "a stream since a time",
+		opts: []gms.GetOption{gms.GenericStream("account-1"), gms.SinceTime(time.Now().Add(-time.Hour))},
+	}, {
+		name: "a category since a time",
+		opts: []gms.GetOption{gms.Category("account"), gms.SinceTime(time.Now().Add(-time.Hour))},
+	}, {
+		name: "a category until a time",
+		opts: []gms.GetOption{gms.Category("account"), gms.UntilTime(time.Now())},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := client.Get(context.Background(), test.opts...)
+			assert.Equal(t, ErrNotSupported, err)
+		})
+	}
+}
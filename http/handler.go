@@ -0,0 +1,291 @@
+// Package http serves reads from a Repository over HTTP as pages of JSON, and reads them back with a client.
+//
+// A stream is read from /streams/{stream name}, and a category from /categories/{category}. Every page holds a token
+// for the page after it, so clients page through a stream or category without knowing about versions or global
+// positions; the last page's token is where new messages will show up, and reading it with a wait long-polls for them.
+//
+// NewServer serves the rest of a Repository as well, writes included, so NewMessageStore can be used in place of a
+// database connection.
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/internal/jsonenvelope"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/sirupsen/logrus"
+)
+
+//Errors returned only over HTTP
+const (
+	ErrInvalidToken        = repository.Error("Token is not a valid token for what's being read")
+	ErrInvalidLimit        = repository.Error("Limit must be a number greater than or equal to zero")
+	ErrInvalidWait         = repository.Error("Wait must be a duration greater than or equal to zero")
+	ErrInvalidPollInterval = repository.Error("Poll interval must be greater than zero")
+	ErrInvalidMaxWait      = repository.Error("Max wait must be greater than or equal to zero")
+	ErrNotSupported        = repository.Error("Read is not supported over HTTP")
+	ErrInvalidRequest      = repository.Error("Request has a missing or malformed parameter")
+)
+
+const (
+	defaultLimit        = 1000 // the most messages in a page
+	defaultPollInterval = 200 * time.Millisecond
+	defaultMaxWait      = 30 * time.Second
+)
+
+//HandlerOption configures a handler when it's created
+type HandlerOption func(h *handler) error
+
+//PollInterval sets how often the repository is read again while waiting for new messages (default 200ms)
+func PollInterval(interval time.Duration) HandlerOption {
+	return func(h *handler) error {
+		if interval <= 0 {
+			return ErrInvalidPollInterval
+		}
+
+		h.pollInterval = interval
+		return nil
+	}
+}
+
+//MaxWait sets the longest a read waits for new messages, whatever wait it asked for (default 30s); zero never waits
+func MaxWait(wait time.Duration) HandlerOption {
+	return func(h *handler) error {
+		if wait < 0 {
+			return ErrInvalidMaxWait
+		}
+
+		h.maxWait = wait
+		return nil
+	}
+}
+
+type handler struct {
+	repo         repository.Repository
+	log          logrus.FieldLogger
+	pollInterval time.Duration
+	maxWait      time.Duration
+	mux          *http.ServeMux
+}
+
+// reader reads a page from a stream or category
+type reader func(ctx context.Context, since int64, limit int, types []string) ([]*repository.MessageEnvelope, error)
+
+//NewHandler creates a handler serving reads from the repository; the query parameters every read takes are:
+//
+//	token  where the page starts, from the page before it; left out, the page starts at the beginning
+//	limit  the most messages in the page, up to 1000 (the default)
+//	wait   how long to wait for new messages when there are none yet, as a duration like "10s"
+//	type   only read messages of the type; repeat it to read several types
+func NewHandler(repo repository.Repository, log logrus.FieldLogger, opts ...HandlerOption) (http.Handler, error) {
+	return newHandler(repo, log, opts...)
+}
+
+// newHandler creates a handler serving pages of streams and categories, which a server serves more from
+func newHandler(repo repository.Repository, log logrus.FieldLogger, opts ...HandlerOption) (*handler, error) {
+	h := &handler{
+		repo:         repo,
+		log:          log,
+		pollInterval: defaultPollInterval,
+		maxWait:      defaultMaxWait,
+		mux:          http.NewServeMux(),
+	}
+
+	for _, option := range opts {
+		if err := option(h); err != nil {
+			return nil, err
+		}
+	}
+
+	h.mux.HandleFunc("/streams/", h.serveStream)
+	h.mux.HandleFunc("/categories/", h.serveCategory)
+
+	return h, nil
+}
+
+//ServeHTTP serves a request
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// serveStream serves a page of a stream, with tokens holding versions
+func (h *handler) serveStream(w http.ResponseWriter, r *http.Request) {
+	streamName := strings.TrimPrefix(r.URL.Path, "/streams/")
+
+	h.servePage(w, r, versionToken, func(ctx context.Context, since int64, limit int, types []string) ([]*repository.MessageEnvelope, error) {
+		return h.repo.GetAllMessagesInStreamSince(ctx, streamName, since, limit, types...)
+	})
+}
+
+// serveCategory serves a page of a category, with tokens holding global positions
+func (h *handler) serveCategory(w http.ResponseWriter, r *http.Request) {
+	category := strings.TrimPrefix(r.URL.Path, "/categories/")
+
+	h.servePage(w, r, positionToken, func(ctx context.Context, since int64, limit int, types []string) ([]*repository.MessageEnvelope, error) {
+		return h.repo.GetAllMessagesInCategorySince(ctx, category, since, limit, types...)
+	})
+}
+
+// servePage reads a page, waiting for new messages when asked to and there are none yet
+func (h *handler) servePage(w http.ResponseWriter, r *http.Request, kind string, read reader) {
+	if !h.allow(w, r, http.MethodGet) {
+		return
+	}
+
+	query := r.URL.Query()
+	since, err := decodeToken(kind, query.Get("token"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	limit, err := parseLimit(query.Get("limit"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	wait, err := parseWait(query.Get("wait"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	if wait > h.maxWait {
+		wait = h.maxWait
+	}
+
+	ctx := r.Context()
+	deadline := time.Now().Add(wait)
+	for {
+		msgEnvelopes, err := read(ctx, since, limit, query["type"])
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+
+		remaining := time.Until(deadline)
+		if len(msgEnvelopes) > 0 || remaining <= 0 {
+			h.writePage(w, kind, since, msgEnvelopes)
+			return
+		}
+
+		if remaining > h.pollInterval {
+			remaining = h.pollInterval
+		}
+		select {
+		case <-ctx.Done():
+			return // whoever was waiting has gone away
+		case <-time.After(remaining):
+		}
+	}
+}
+
+// allow checks a request was made with the method, writing an error when it wasn't
+func (h *handler) allow(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+
+	w.Header().Set("Allow", method)
+	h.writeJSON(w, http.StatusMethodNotAllowed, failure{Error: http.StatusText(http.StatusMethodNotAllowed)})
+	return false
+}
+
+// writePage writes the messages, along with the token for the page after them; with no messages, that's the same page
+func (h *handler) writePage(w http.ResponseWriter, kind string, since int64, msgEnvelopes []*repository.MessageEnvelope) {
+	next := since
+	if len(msgEnvelopes) > 0 {
+		last := msgEnvelopes[len(msgEnvelopes)-1]
+		next = last.GlobalPosition + 1
+		if kind == versionToken {
+			next = last.Version + 1
+		}
+	}
+
+	msgs := make([]jsonenvelope.Record, len(msgEnvelopes))
+	for i, env := range msgEnvelopes {
+		msgs[i] = jsonenvelope.FromEnvelope(env)
+	}
+
+	h.writeJSON(w, http.StatusOK, page{Messages: msgs, Next: encodeToken(kind, next)})
+}
+
+// writeError writes an error, hiding the details of anything that isn't the request's fault
+func (h *handler) writeError(w http.ResponseWriter, err error) {
+	status := statusOf(err)
+	if status == http.StatusInternalServerError {
+		h.log.WithError(err).Error("Failure in http/handler.go::writeError")
+		err = repository.Error(http.StatusText(status))
+	}
+
+	h.writeJSON(w, status, failure{Error: err.Error()})
+}
+
+// writeJSON writes a response
+func (h *handler) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.log.WithError(err).Debug("Failure in http/handler.go::writeJSON") // nothing more can be said to whoever asked
+	}
+}
+
+// statusOf gets the status an error is returned with
+func statusOf(err error) int {
+	switch err {
+	case ErrInvalidToken,
+		ErrInvalidLimit,
+		ErrInvalidWait,
+		ErrInvalidRequest,
+		repository.ErrInvalidStreamName,
+		repository.ErrBlankCategory,
+		repository.ErrInvalidCategory,
+		repository.ErrNegativeBatchSize,
+		repository.ErrMessageNoID,
+		repository.ErrNilMessage,
+		repository.ErrInvalidPosition:
+		return http.StatusBadRequest
+	case repository.ErrExpectedVersionFailed,
+		repository.ErrDuplicateMessageID:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseLimit gets the most messages in a page, which is the default when it's left out, zero, or greater than it
+func parseLimit(value string) (int, error) {
+	if value == "" {
+		return defaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0, ErrInvalidLimit
+	}
+	if limit == 0 || limit > defaultLimit {
+		return defaultLimit, nil
+	}
+
+	return limit, nil
+}
+
+// parseWait gets how long to wait for new messages, which is not at all when it's left out
+func parseWait(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	wait, err := time.ParseDuration(value)
+	if err != nil || wait < 0 {
+		return 0, ErrInvalidWait
+	}
+
+	return wait, nil
+}
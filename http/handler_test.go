@@ -0,0 +1,167 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/blackhatbrigade/gomessagestore/http"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/repository/inmemory"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// newServer serves reads from a new in memory repository, failing the test on any error
+func newServer(t *testing.T, opts ...HandlerOption) (*httptest.Server, repository.Repository) {
+	log := logrus.New()
+	log.Out = ioutil.Discard
+
+	repo := inmemory.NewInMemoryRepository(nil)
+	handler, err := NewHandler(repo, log, opts...)
+	if err != nil {
+		t.Fatalf("failed to create handler: %s", err)
+	}
+
+	return httptest.NewServer(handler), repo
+}
+
+// newClient creates a client for the server
+func newClient(server *httptest.Server) *Client {
+	log := logrus.New()
+	log.Out = ioutil.Discard
+
+	return NewClient(server.URL, log, HTTPClient(server.Client()))
+}
+
+// writeMessages writes a message of each type to a stream, failing the test on any error
+func writeMessages(t *testing.T, repo repository.Repository, stream string, types ...string) {
+	for _, msgType := range types {
+		err := repo.WriteMessage(context.Background(), &repository.MessageEnvelope{
+			ID:          uuid.NewRandom(),
+			StreamName:  stream,
+			MessageType: msgType,
+			Data:        []byte(`{"some":"data"}`),
+		})
+		if err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+	}
+}
+
+func TestHandlerServesStreamAsJSON(t *testing.T) {
+	server, repo := newServer(t)
+	defer server.Close()
+	writeMessages(t, repo, "account-1", "Opened", "Deposited")
+
+	res, err := http.Get(server.URL + "/streams/account-1")
+	if err != nil {
+		t.Fatalf("failed to read stream: %s", err)
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		Messages []struct {
+			StreamName  string          `json:"streamName"`
+			MessageType string          `json:"messageType"`
+			Version     int64           `json:"version"`
+			Data        json.RawMessage `json:"data"`
+			Metadata    json.RawMessage `json:"metadata"`
+		} `json:"messages"`
+		Next string `json:"next"`
+	}
+	assert.Nil(t, json.NewDecoder(res.Body).Decode(&body))
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	if assert.Len(t, body.Messages, 2) {
+		assert.Equal(t, "account-1", body.Messages[0].StreamName)
+		assert.Equal(t, "Deposited", body.Messages[1].MessageType)
+		assert.Equal(t, int64(1), body.Messages[1].Version)
+		assert.JSONEq(t, `{"some":"data"}`, string(body.Messages[0].Data))
+		assert.Equal(t, "null", string(body.Messages[0].Metadata))
+	}
+	assert.NotEmpty(t, body.Next)
+}
+
+func TestHandlerRejectsBadRequests(t *testing.T) {
+	server, _ := newServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		url    string
+		status int
+		err    string
+	}{{
+		name:   "when the token isn't a token",
+		url:    "/streams/account-1?token=nonsense!",
+		status: http.StatusBadRequest,
+		err:    ErrInvalidToken.Error(),
+	}, {
+		name:   "when the token is for a category rather than a stream",
+		url:    "/streams/account-1?token=cG9zaXRpb246Mw", // position:3
+		status: http.StatusBadRequest,
+		err:    ErrInvalidToken.Error(),
+	}, {
+		name:   "when the limit is negative",
+		url:    "/categories/account?limit=-1",
+		status: http.StatusBadRequest,
+		err:    ErrInvalidLimit.Error(),
+	}, {
+		name:   "when the wait isn't a duration",
+		url:    "/categories/account?wait=forever",
+		status: http.StatusBadRequest,
+		err:    ErrInvalidWait.Error(),
+	}, {
+		name:   "when the category has a hyphen",
+		url:    "/categories/account-1",
+		status: http.StatusBadRequest,
+		err:    repository.ErrInvalidCategory.Error(),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := http.Get(server.URL + test.url)
+			if err != nil {
+				t.Fatalf("failed to make request: %s", err)
+			}
+			defer res.Body.Close()
+
+			var body struct {
+				Error string `json:"error"`
+			}
+			assert.Nil(t, json.NewDecoder(res.Body).Decode(&body))
+			assert.Equal(t, test.status, res.StatusCode)
+			assert.Equal(t, test.err, body.Error)
+		})
+	}
+}
+
+func TestHandlerOnlyServesGets(t *testing.T) {
+	server, _ := newServer(t)
+	defer server.Close()
+
+	res, err := http.Post(server.URL+"/streams/account-1", "application/json", nil)
+	if err != nil {
+		t.Fatalf("failed to make request: %s", err)
+	}
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+}
+
+func TestHandlerOptionErrors(t *testing.T) {
+	repo := inmemory.NewInMemoryRepository(nil)
+
+	_, err := NewHandler(repo, logrus.New(), PollInterval(0))
+	assert.Equal(t, ErrInvalidPollInterval, err)
+
+	_, err = NewHandler(repo, logrus.New(), MaxWait(-time.Second))
+	assert.Equal(t, ErrInvalidMaxWait, err)
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blackhatbrigade/gomessagestore/internal/jsonenvelope"
+)
+
+// page is a page of messages, along with the token that reads the page after it
+type page struct {
+	Messages []jsonenvelope.Record `json:"messages"`
+	Next     string                `json:"next"`
+}
+
+// failure is the body of every response that isn't a success
+type failure struct {
+	Error string `json:"error"`
+}
+
+// tokens are opaque to clients, but all they hold is where the next page starts: a version in a stream, or a global position otherwise
+const (
+	versionToken  = "version"
+	positionToken = "position"
+)
+
+// encodeToken makes the token for the page starting at a version or global position
+func encodeToken(kind string, position int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%d", kind, position)))
+}
+
+// decodeToken gets the version or global position a token starts at; no token starts at the beginning
+func decodeToken(kind string, token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 || parts[0] != kind {
+		return 0, ErrInvalidToken // tokens for a stream can't be used to read a category, and the other way around
+	}
+
+	position, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || position < 0 {
+		return 0, ErrInvalidToken
+	}
+
+	return position, nil
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+)
+
+// remoteRepository is a Repository reading pages from a handler, so a message store can be put in front of it
+type remoteRepository struct {
+	client *Client
+}
+
+// readSince reads a page as envelopes, with a limit and types in place of read options
+func (repo *remoteRepository) readSince(ctx context.Context, prefix, name, kind string, since int64, batchSize int, types []string) ([]*repository.MessageEnvelope, error) {
+	if batchSize < 0 {
+		return nil, repository.ErrNegativeBatchSize
+	}
+
+	p, err := repo.client.read(ctx, prefix, name, encodeToken(kind, since), Limit(batchSize), Types(types...))
+	if err != nil {
+		return nil, err
+	}
+
+	msgEnvelopes := make([]*repository.MessageEnvelope, len(p.Messages))
+	for i, msg := range p.Messages {
+		msgEnvelopes[i] = msg.ToEnvelope()
+	}
+
+	return msgEnvelopes, nil
+}
+
+//WriteMessage isn't supported over HTTP
+func (repo *remoteRepository) WriteMessage(ctx context.Context, message *repository.MessageEnvelope) error {
+	return ErrNotSupported
+}
+
+//WriteMessageWithExpectedPosition isn't supported over HTTP
+func (repo *remoteRepository) WriteMessageWithExpectedPosition(ctx context.Context, message *repository.MessageEnvelope, position int64) error {
+	return ErrNotSupported
+}
+
+//GetAllMessagesInStream reads a stream from the beginning
+func (repo *remoteRepository) GetAllMessagesInStream(ctx context.Context, streamName string, batchSize int) ([]*repository.MessageEnvelope, error) {
+	return repo.GetAllMessagesInStreamSince(ctx, streamName, 0, batchSize)
+}
+
+//GetAllMessagesInStreamSince reads a stream from the version on
+func (repo *remoteRepository) GetAllMessagesInStreamSince(ctx context.Context, streamName string, version int64, batchSize int, types ...string) ([]*repository.MessageEnvelope, error) {
+	if version < 0 {
+		version = 0 // tokens can't hold negative versions, and there's nothing before the first message anyway
+	}
+
+	return repo.readSince(ctx, "/streams/", streamName, versionToken, version, batchSize, types)
+}
+
+//GetLastMessageInStream isn't supported over HTTP
+func (repo *remoteRepository) GetLastMessageInStream(ctx context.Context, streamName string) (*repository.MessageEnvelope, error) {
+	return nil, ErrNotSupported
+}
+
+//GetStreamVersion isn't supported over HTTP
+func (repo *remoteRepository) GetStreamVersion(ctx context.Context, streamName string) (int64, error) {
+	return 0, ErrNotSupported
+}
+
+//GetStreamVersionAtTime isn't supported over HTTP
+func (repo *remoteRepository) GetStreamVersionAtTime(ctx context.Context, streamName string, t time.Time) (int64, error) {
+	return 0, ErrNotSupported
+}
+
+//GetAllMessagesInStreamBackward isn't supported over HTTP
+func (repo *remoteRepository) GetAllMessagesInStreamBackward(ctx context.Context, streamName string, version int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+	return nil, ErrNotSupported
+}
+
+//GetAllMessagesInCategory reads a category from the beginning
+func (repo *remoteRepository) GetAllMessagesInCategory(ctx context.Context, category string, batchSize int) ([]*repository.MessageEnvelope, error) {
+	return repo.GetAllMessagesInCategorySince(ctx, category, 0, batchSize)
+}
+
+//GetAllMessagesInCategorySince reads a category from the global position on
+func (repo *remoteRepository) GetAllMessagesInCategorySince(ctx context.Context, category string, globalPosition int64, batchSize int, types ...string) ([]*repository.MessageEnvelope, error) {
+	if globalPosition < 0 {
+		globalPosition = 0
+	}
+
+	return repo.readSince(ctx, "/categories/", category, positionToken, globalPosition, batchSize, types)
+}
+
+//GetAllMessagesInCategoryBackward isn't supported over HTTP
+func (repo *remoteRepository) GetAllMessagesInCategoryBackward(ctx context.Context, category string, globalPosition int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+	return nil, ErrNotSupported
+}
+
+//GetAllMessagesSince isn't supported over HTTP
+func (repo *remoteRepository) GetAllMessagesSince(ctx context.Context, globalPosition int64, batchSize int, filter repository.CategoryFilter, types ...string) ([]*repository.MessageEnvelope, error) {
+	return nil, ErrNotSupported
+}
+
+//GetGlobalPositionAtTime isn't supported over HTTP
+func (repo *remoteRepository) GetGlobalPositionAtTime(ctx context.Context, t time.Time) (int64, error) {
+	return 0, ErrNotSupported
+}
@@ -0,0 +1,260 @@
+package http
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/internal/jsonenvelope"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// writeRequest is a message to write, along with the version its stream has to be at when there is one
+type writeRequest struct {
+	Message         *jsonenvelope.Record `json:"message"`
+	ExpectedVersion *int64               `json:"expectedVersion"`
+}
+
+// messagesResponse is the messages a read found
+type messagesResponse struct {
+	Messages []jsonenvelope.Record `json:"messages"`
+}
+
+// lastMessageResponse is the last message in a stream, null when it has none
+type lastMessageResponse struct {
+	Message *jsonenvelope.Record `json:"message"`
+}
+
+// versionResponse is a stream's version
+type versionResponse struct {
+	Version int64 `json:"version"`
+}
+
+// positionResponse is a global position
+type positionResponse struct {
+	Position int64 `json:"position"`
+}
+
+//NewServer creates a handler serving everything a repository does, so NewMessageStore can write and read through it
+//from services that can't reach the database. It serves the pages NewHandler does, too. The repository is served from:
+//
+//	POST /store/messages        writes {"message": ..., "expectedVersion": ...}, 409 when the version is wrong
+//	GET  /store/stream          reads ?name=, from ?since= on (or back, with ?backward=true), ?batchSize= (up to 1000) at a time, of any ?type=
+//	GET  /store/stream/last     reads the last message in the stream ?name=
+//	GET  /store/stream/version  gets the version of the stream ?name=, or its version at ?time=
+//	GET  /store/category        reads like /store/stream, with global positions
+//	GET  /store/all             reads every category, or only those in ?include= and not in ?exclude=
+//	GET  /store/position        gets the global position at ?time=
+func NewServer(repo repository.Repository, log logrus.FieldLogger, opts ...HandlerOption) (http.Handler, error) {
+	h, err := newHandler(repo, log, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mux.HandleFunc("/store/messages", h.serveWrite)
+	h.mux.HandleFunc("/store/stream", h.serveStreamRead)
+	h.mux.HandleFunc("/store/stream/last", h.serveLastMessage)
+	h.mux.HandleFunc("/store/stream/version", h.serveStreamVersion)
+	h.mux.HandleFunc("/store/category", h.serveCategoryRead)
+	h.mux.HandleFunc("/store/all", h.serveAllRead)
+	h.mux.HandleFunc("/store/position", h.servePositionAtTime)
+
+	return h, nil
+}
+
+// serveWrite writes a message, at an expected version when there's one
+func (h *handler) serveWrite(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(w, r, http.MethodPost) {
+		return
+	}
+
+	var req writeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, ErrInvalidRequest)
+		return
+	}
+
+	var env *repository.MessageEnvelope
+	if req.Message != nil {
+		env = req.Message.ToEnvelope()
+	}
+
+	var err error
+	if req.ExpectedVersion != nil {
+		err = h.repo.WriteMessageWithExpectedPosition(r.Context(), env, *req.ExpectedVersion)
+	} else {
+		err = h.repo.WriteMessage(r.Context(), env)
+	}
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveStreamRead reads from a stream, forward or backward
+func (h *handler) serveStreamRead(w http.ResponseWriter, r *http.Request) {
+	h.serveRead(w, r, func(query url.Values, since int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+		if query.Get("backward") == "true" {
+			return h.repo.GetAllMessagesInStreamBackward(r.Context(), query.Get("name"), since, batchSize)
+		}
+
+		return h.repo.GetAllMessagesInStreamSince(r.Context(), query.Get("name"), since, batchSize, query["type"]...)
+	})
+}
+
+// serveCategoryRead reads from a category, forward or backward
+func (h *handler) serveCategoryRead(w http.ResponseWriter, r *http.Request) {
+	h.serveRead(w, r, func(query url.Values, since int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+		if query.Get("backward") == "true" {
+			return h.repo.GetAllMessagesInCategoryBackward(r.Context(), query.Get("name"), since, batchSize)
+		}
+
+		return h.repo.GetAllMessagesInCategorySince(r.Context(), query.Get("name"), since, batchSize, query["type"]...)
+	})
+}
+
+// serveAllRead reads from every category the filter matches
+func (h *handler) serveAllRead(w http.ResponseWriter, r *http.Request) {
+	h.serveRead(w, r, func(query url.Values, since int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+		filter := repository.CategoryFilter{
+			Include: query["include"],
+			Exclude: query["exclude"],
+		}
+
+		return h.repo.GetAllMessagesSince(r.Context(), since, batchSize, filter, query["type"]...)
+	})
+}
+
+// serveRead parses where a read starts and how many messages it reads, then writes the messages read
+func (h *handler) serveRead(w http.ResponseWriter, r *http.Request, read func(query url.Values, since int64, batchSize int) ([]*repository.MessageEnvelope, error)) {
+	if !h.allow(w, r, http.MethodGet) {
+		return
+	}
+
+	query := r.URL.Query()
+	since := int64(0)
+	if query.Get("backward") == "true" {
+		since = math.MaxInt64 // backward reads start from the newest message
+	}
+	since, err := parseInt(query.Get("since"), since)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	batchSize, err := parseLimit(query.Get("batchSize"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	msgEnvelopes, err := read(query, since, batchSize)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	msgs := make([]jsonenvelope.Record, len(msgEnvelopes))
+	for i, env := range msgEnvelopes {
+		msgs[i] = jsonenvelope.FromEnvelope(env)
+	}
+
+	h.writeJSON(w, http.StatusOK, messagesResponse{Messages: msgs})
+}
+
+// serveLastMessage reads the last message in a stream
+func (h *handler) serveLastMessage(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(w, r, http.MethodGet) {
+		return
+	}
+
+	env, err := h.repo.GetLastMessageInStream(r.Context(), r.URL.Query().Get("name"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	res := lastMessageResponse{}
+	if env != nil {
+		msg := jsonenvelope.FromEnvelope(env)
+		res.Message = &msg
+	}
+
+	h.writeJSON(w, http.StatusOK, res)
+}
+
+// serveStreamVersion gets a stream's version, now or at a time
+func (h *handler) serveStreamVersion(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(w, r, http.MethodGet) {
+		return
+	}
+
+	query := r.URL.Query()
+	var version int64
+	var err error
+	if query.Get("time") == "" {
+		version, err = h.repo.GetStreamVersion(r.Context(), query.Get("name"))
+	} else {
+		var t time.Time
+		if t, err = parseTime(query.Get("time")); err == nil {
+			version, err = h.repo.GetStreamVersionAtTime(r.Context(), query.Get("name"), t)
+		}
+	}
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, versionResponse{Version: version})
+}
+
+// servePositionAtTime gets the global position at a time
+func (h *handler) servePositionAtTime(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(w, r, http.MethodGet) {
+		return
+	}
+
+	t, err := parseTime(r.URL.Query().Get("time"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	position, err := h.repo.GetGlobalPositionAtTime(r.Context(), t)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, positionResponse{Position: position})
+}
+
+// parseInt parses a number, which is the default when it's left out
+func parseInt(value string, defaultValue int64) (int64, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidRequest
+	}
+
+	return n, nil
+}
+
+// parseTime parses a time, which has to be there
+func parseTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, ErrInvalidRequest
+	}
+
+	return t, nil
+}
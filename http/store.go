@@ -0,0 +1,193 @@
+package http
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	gms "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/internal/jsonenvelope"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/sirupsen/logrus"
+)
+
+//NewMessageStore creates a message store that writes and reads through a server created with NewServer at the base URL,
+//so handlers, projectors and subscribers work against it just like they do against a database. Writing with AtPosition
+//returns gms.ErrExpectedVersionFailed when the stream is at another version, and WithTx isn't supported.
+func NewMessageStore(baseURL string, log logrus.FieldLogger, opts ...ClientOption) gms.MessageStore {
+	return gms.NewMessageStoreFromRepository(NewRepository(baseURL, log, opts...), log)
+}
+
+//NewRepository creates a Repository that writes and reads through a server created with NewServer at the base URL.
+//The server reads at most 1000 messages at a time, so reads of more than that (and reads with a batch size of 0, which read
+//every message there is) take a request for each 1000 messages.
+func NewRepository(baseURL string, log logrus.FieldLogger, opts ...ClientOption) repository.Repository {
+	return &storeRepository{client: NewClient(baseURL, log, opts...)}
+}
+
+// storeRepository is a Repository making requests to a server for everything
+type storeRepository struct {
+	client *Client
+}
+
+// readMessages reads the messages from a read endpoint. The server reads at most 1000 messages at a time, so bigger batches
+// (and a batch size of 0, which reads every message there is) are read a page at a time, each page starting where next says
+// the last one left off.
+func (repo *storeRepository) readMessages(ctx context.Context, path string, values url.Values, since int64, batchSize int, types []string, next func(last *repository.MessageEnvelope) int64) ([]*repository.MessageEnvelope, error) {
+	if batchSize < 0 {
+		return nil, repository.ErrNegativeBatchSize
+	}
+
+	for _, msgType := range types {
+		values.Add("type", msgType)
+	}
+
+	msgEnvelopes := []*repository.MessageEnvelope{}
+	for {
+		pageSize := defaultLimit
+		if remaining := batchSize - len(msgEnvelopes); batchSize > 0 && remaining < pageSize {
+			pageSize = remaining
+		}
+
+		values.Set("since", strconv.FormatInt(since, 10))
+		values.Set("batchSize", strconv.Itoa(pageSize))
+
+		var res messagesResponse
+		if err := repo.client.get(ctx, path, values, &res); err != nil {
+			return nil, err
+		}
+
+		for _, msg := range res.Messages {
+			msgEnvelopes = append(msgEnvelopes, msg.ToEnvelope())
+		}
+		if len(res.Messages) < pageSize || len(msgEnvelopes) == batchSize {
+			return msgEnvelopes, nil
+		}
+
+		since = next(msgEnvelopes[len(msgEnvelopes)-1])
+		if since < 0 {
+			return msgEnvelopes, nil // a backward read that's reached the beginning
+		}
+	}
+}
+
+// afterVersion is where the page after one ending with a message starts, reading a stream forward
+func afterVersion(last *repository.MessageEnvelope) int64 {
+	return last.Version + 1
+}
+
+// afterPosition is where the page after one ending with a message starts, reading a category forward
+func afterPosition(last *repository.MessageEnvelope) int64 {
+	return last.GlobalPosition + 1
+}
+
+// beforeVersion is where the page after one ending with a message starts, reading a stream backward
+func beforeVersion(last *repository.MessageEnvelope) int64 {
+	return last.Version - 1
+}
+
+// beforePosition is where the page after one ending with a message starts, reading a category backward
+func beforePosition(last *repository.MessageEnvelope) int64 {
+	return last.GlobalPosition - 1
+}
+
+// write writes a message, at an expected version when there's one
+func (repo *storeRepository) write(ctx context.Context, env *repository.MessageEnvelope, expectedVersion *int64) error {
+	if env == nil {
+		return repository.ErrNilMessage
+	}
+
+	msg := jsonenvelope.FromEnvelope(env)
+	return repo.client.post(ctx, "/store/messages", writeRequest{Message: &msg, ExpectedVersion: expectedVersion})
+}
+
+//WriteMessage writes a message
+func (repo *storeRepository) WriteMessage(ctx context.Context, message *repository.MessageEnvelope) error {
+	return repo.write(ctx, message, nil)
+}
+
+//WriteMessageWithExpectedPosition writes a message, as long as the stream is at the version
+func (repo *storeRepository) WriteMessageWithExpectedPosition(ctx context.Context, message *repository.MessageEnvelope, position int64) error {
+	return repo.write(ctx, message, &position)
+}
+
+//GetAllMessagesInStream reads a stream from the beginning
+func (repo *storeRepository) GetAllMessagesInStream(ctx context.Context, streamName string, batchSize int) ([]*repository.MessageEnvelope, error) {
+	return repo.GetAllMessagesInStreamSince(ctx, streamName, 0, batchSize)
+}
+
+//GetAllMessagesInStreamSince reads a stream from the version on
+func (repo *storeRepository) GetAllMessagesInStreamSince(ctx context.Context, streamName string, version int64, batchSize int, types ...string) ([]*repository.MessageEnvelope, error) {
+	return repo.readMessages(ctx, "/store/stream", url.Values{"name": {streamName}}, version, batchSize, types, afterVersion)
+}
+
+//GetLastMessageInStream reads the last message in a stream, nil when it has none
+func (repo *storeRepository) GetLastMessageInStream(ctx context.Context, streamName string) (*repository.MessageEnvelope, error) {
+	var res lastMessageResponse
+	if err := repo.client.get(ctx, "/store/stream/last", url.Values{"name": {streamName}}, &res); err != nil {
+		return nil, err
+	}
+	if res.Message == nil {
+		return nil, nil
+	}
+
+	return res.Message.ToEnvelope(), nil
+}
+
+//GetStreamVersion gets the version of the last message in a stream, -1 when it has none
+func (repo *storeRepository) GetStreamVersion(ctx context.Context, streamName string) (int64, error) {
+	var res versionResponse
+	if err := repo.client.get(ctx, "/store/stream/version", url.Values{"name": {streamName}}, &res); err != nil {
+		return 0, err
+	}
+
+	return res.Version, nil
+}
+
+//GetStreamVersionAtTime gets the version of the first message in a stream written at or after a time
+func (repo *storeRepository) GetStreamVersionAtTime(ctx context.Context, streamName string, t time.Time) (int64, error) {
+	var res versionResponse
+	values := url.Values{"name": {streamName}, "time": {t.Format(time.RFC3339Nano)}}
+	if err := repo.client.get(ctx, "/store/stream/version", values, &res); err != nil {
+		return 0, err
+	}
+
+	return res.Version, nil
+}
+
+//GetAllMessagesInStreamBackward reads a stream from the version back
+func (repo *storeRepository) GetAllMessagesInStreamBackward(ctx context.Context, streamName string, version int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+	return repo.readMessages(ctx, "/store/stream", url.Values{"name": {streamName}, "backward": {"true"}}, version, batchSize, nil, beforeVersion)
+}
+
+//GetAllMessagesInCategory reads a category from the beginning
+func (repo *storeRepository) GetAllMessagesInCategory(ctx context.Context, category string, batchSize int) ([]*repository.MessageEnvelope, error) {
+	return repo.GetAllMessagesInCategorySince(ctx, category, 0, batchSize)
+}
+
+//GetAllMessagesInCategorySince reads a category from the global position on
+func (repo *storeRepository) GetAllMessagesInCategorySince(ctx context.Context, category string, globalPosition int64, batchSize int, types ...string) ([]*repository.MessageEnvelope, error) {
+	return repo.readMessages(ctx, "/store/category", url.Values{"name": {category}}, globalPosition, batchSize, types, afterPosition)
+}
+
+//GetAllMessagesInCategoryBackward reads a category from the global position back
+func (repo *storeRepository) GetAllMessagesInCategoryBackward(ctx context.Context, category string, globalPosition int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+	return repo.readMessages(ctx, "/store/category", url.Values{"name": {category}, "backward": {"true"}}, globalPosition, batchSize, nil, beforePosition)
+}
+
+//GetAllMessagesSince reads every category the filter matches from the global position on
+func (repo *storeRepository) GetAllMessagesSince(ctx context.Context, globalPosition int64, batchSize int, filter repository.CategoryFilter, types ...string) ([]*repository.MessageEnvelope, error) {
+	values := url.Values{"include": filter.Include, "exclude": filter.Exclude}
+	return repo.readMessages(ctx, "/store/all", values, globalPosition, batchSize, types, afterPosition)
+}
+
+//GetGlobalPositionAtTime gets the global position of the first message written at or after a time
+func (repo *storeRepository) GetGlobalPositionAtTime(ctx context.Context, t time.Time) (int64, error) {
+	var res positionResponse
+	if err := repo.client.get(ctx, "/store/position", url.Values{"time": {t.Format(time.RFC3339Nano)}}, &res); err != nil {
+		return 0, err
+	}
+
+	return res.Position, nil
+}
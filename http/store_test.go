@@ -0,0 +1,207 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gms "github.com/blackhatbrigade/gomessagestore"
+	. "github.com/blackhatbrigade/gomessagestore/http"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/repository/inmemory"
+	"github.com/blackhatbrigade/gomessagestore/repository/repositorytest"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// newStoreServer serves everything a new in memory repository does, failing the test on any error
+func newStoreServer(t *testing.T) *httptest.Server {
+	repo := inmemory.NewInMemoryRepository(nil)
+	server, err := NewServer(repo, logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create server: %s", err)
+	}
+
+	return httptest.NewServer(server)
+}
+
+// newStore creates a message store writing and reading through the server
+func newStore(server *httptest.Server) gms.MessageStore {
+	return NewMessageStore(server.URL, logrus.New(), HTTPClient(server.Client()))
+}
+
+// recorder is a handler sending every message it processes on a channel
+type recorder struct {
+	msgType string
+	msgs    chan gms.Message
+}
+
+func (r recorder) Type() string {
+	return r.msgType
+}
+
+func (r recorder) Process(ctx context.Context, msg gms.Message) error {
+	r.msgs <- msg
+	return nil
+}
+
+func TestRemoteRepoConformance(t *testing.T) {
+	var servers []*httptest.Server
+	repositorytest.RunConformance(t, func(t *testing.T) repository.Repository {
+		server := newStoreServer(t)
+		servers = append(servers, server)
+
+		return NewRepository(server.URL, logrus.New(), HTTPClient(server.Client()))
+	})
+
+	for _, server := range servers {
+		server.Close()
+	}
+}
+
+func TestServerReturnsConflictForWrongExpectedVersion(t *testing.T) {
+	server := newStoreServer(t)
+	defer server.Close()
+
+	body := `{"message": {"id": "4a4c5a86-5b3a-4c5e-8a43-9c1c3cb1b0e6", "streamName": "account-1", "messageType": "Opened"}, "expectedVersion": 3}`
+	res, err := http.Post(server.URL+"/store/messages", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to make request: %s", err)
+	}
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusConflict, res.StatusCode)
+}
+
+func TestServerLimitsBatchSize(t *testing.T) {
+	repo := inmemory.NewInMemoryRepository(nil)
+	handler, err := NewServer(repo, logrus.New())
+	if err != nil {
+		t.Fatalf("failed to create server: %s", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	for i := 0; i < 1001; i++ {
+		writeMessages(t, repo, "account-1", "Deposited")
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		status   int
+		expected int
+	}{{
+		name:     "when the batch size is over the limit, the limit is read",
+		query:    "?name=account-1&batchSize=5000",
+		status:   http.StatusOK,
+		expected: 1000,
+	}, {
+		name:     "when the batch size is 0, the limit is read",
+		query:    "?name=account-1&batchSize=0",
+		status:   http.StatusOK,
+		expected: 1000,
+	}, {
+		name:   "when the batch size is negative, the request is rejected",
+		query:  "?name=account-1&batchSize=-1",
+		status: http.StatusBadRequest,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := http.Get(server.URL + "/store/stream" + test.query)
+			if err != nil {
+				t.Fatalf("failed to make request: %s", err)
+			}
+			defer res.Body.Close()
+
+			var body struct {
+				Messages []json.RawMessage `json:"messages"`
+			}
+			assert.Nil(t, json.NewDecoder(res.Body).Decode(&body))
+			assert.Equal(t, test.status, res.StatusCode)
+			assert.Len(t, body.Messages, test.expected)
+		})
+	}
+}
+
+func TestRemoteMessageStoreWritesAtPosition(t *testing.T) {
+	server := newStoreServer(t)
+	defer server.Close()
+	ms := newStore(server)
+	entityID := gms.NewID()
+	ctx := context.Background()
+
+	opened := gms.NewEvent(gms.NewID(), entityID, "account", "Opened", []byte(`{}`), nil)
+	assert.Nil(t, ms.Write(ctx, opened, gms.AtPosition(-1)))
+
+	again := gms.NewEvent(gms.NewID(), entityID, "account", "Opened", []byte(`{}`), nil)
+	assert.Equal(t, gms.ErrExpectedVersionFailed, ms.Write(ctx, again, gms.AtPosition(-1)))
+
+	version, err := ms.StreamVersion(ctx, "account-"+entityID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), version)
+
+	_, err = ms.WithTx(nil)
+	assert.Equal(t, gms.ErrRepositoryDoesNotSupportTransactions, err)
+}
+
+func TestRemoteMessageStoreRunsProjectors(t *testing.T) {
+	server := newStoreServer(t)
+	defer server.Close()
+	ms := newStore(server)
+	entityID := gms.NewID()
+	ctx := context.Background()
+
+	for _, msgType := range []string{"Deposited", "Deposited", "Withdrawn"} {
+		event := gms.NewEvent(gms.NewID(), entityID, "account", msgType, []byte(`{}`), nil)
+		assert.Nil(t, ms.Write(ctx, event))
+	}
+
+	projector, err := ms.CreateProjector(
+		gms.DefaultState(0),
+		gms.WithReducerFunc("Deposited", func(msg gms.Message, state interface{}) (interface{}, error) {
+			return state.(int) + 1, nil
+		}),
+		gms.WithReducerFunc("Withdrawn", func(msg gms.Message, state interface{}) (interface{}, error) {
+			return state.(int) - 1, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create projector: %s", err)
+	}
+
+	state, err := projector.Run(ctx, "account", entityID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, state)
+}
+
+func TestRemoteMessageStoreRunsSubscribers(t *testing.T) {
+	server := newStoreServer(t)
+	defer server.Close()
+	ms := newStore(server)
+	entityID := gms.NewID()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handler := recorder{msgType: "Opened", msgs: make(chan gms.Message, 1)}
+	subscriber, err := ms.CreateSubscriber("accountSubscriber", []gms.MessageHandler{handler}, gms.SubscribeToCategory("account"), gms.PollTime(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create subscriber: %s", err)
+	}
+	go subscriber.Start(ctx)
+
+	opened := gms.NewEvent(gms.NewID(), entityID, "account", "Opened", []byte(`{}`), nil)
+	assert.Nil(t, ms.Write(ctx, opened))
+
+	select {
+	case msg := <-handler.msgs:
+		assert.Equal(t, "Opened", msg.Type())
+		assert.Equal(t, int64(1), msg.Position())
+	case <-time.After(5 * time.Second):
+		t.Error("subscriber never handled the message written")
+	}
+}
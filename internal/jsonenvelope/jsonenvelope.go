@@ -0,0 +1,75 @@
+// Package jsonenvelope is how messages are written as JSON wherever they leave the process, whether that's a line of an export,
+// a response over HTTP or a message being logged: data and metadata are embedded as JSON rather than base64 encoded.
+package jsonenvelope
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+)
+
+// Record is a MessageEnvelope as it's written as JSON
+type Record struct {
+	ID             uuid.UUID       `json:"id"`
+	EntityID       uuid.UUID       `json:"entityId"`
+	StreamName     string          `json:"streamName"`
+	StreamCategory string          `json:"streamCategory"`
+	MessageType    string          `json:"messageType"`
+	Version        int64           `json:"version"`
+	GlobalPosition int64           `json:"globalPosition"`
+	Data           json.RawMessage `json:"data"`
+	Metadata       json.RawMessage `json:"metadata"`
+	Time           time.Time       `json:"time"`
+}
+
+// FromEnvelope gets a message ready to be written as JSON
+func FromEnvelope(env *repository.MessageEnvelope) Record {
+	return Record{
+		ID:             env.ID,
+		EntityID:       env.EntityID,
+		StreamName:     env.StreamName,
+		StreamCategory: env.StreamCategory,
+		MessageType:    env.MessageType,
+		Version:        env.Version,
+		GlobalPosition: env.GlobalPosition,
+		Data:           Raw(env.Data),
+		Metadata:       Raw(env.Metadata),
+		Time:           env.Time,
+	}
+}
+
+// ToEnvelope turns a record back into a message
+func (rec Record) ToEnvelope() *repository.MessageEnvelope {
+	return &repository.MessageEnvelope{
+		ID:             rec.ID,
+		EntityID:       rec.EntityID,
+		StreamName:     rec.StreamName,
+		StreamCategory: rec.StreamCategory,
+		MessageType:    rec.MessageType,
+		Version:        rec.Version,
+		GlobalPosition: rec.GlobalPosition,
+		Data:           FromRaw(rec.Data),
+		Metadata:       FromRaw(rec.Metadata),
+		Time:           rec.Time,
+	}
+}
+
+// Raw embeds JSON as is, with null for messages that have none
+func Raw(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return json.RawMessage("null")
+	}
+
+	return json.RawMessage(data)
+}
+
+// FromRaw undoes Raw
+func FromRaw(data json.RawMessage) []byte {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+
+	return []byte(data)
+}
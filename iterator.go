@@ -0,0 +1,149 @@
+package gomessagestore
+
+import (
+	"context"
+)
+
+// MessageIterator hands out the messages Iterate reads one at a time, reading the next batch from the repository when it runs out:
+//
+//	it := messageStore.Iterate(ctx, gms.Category("account"))
+//	defer it.Close()
+//	for it.Next() {
+//		handle(it.Message())
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type MessageIterator interface {
+	Next() bool       // moves on to the next message, false once there are none left or the iterator stopped on an error
+	Message() Message // the message Next moved on to
+	Err() error       // the error that stopped the iterator, if any; the context's error when it was cancelled part way through
+	Close() error     // stops the iterator early, after which Next returns false
+}
+
+type messageIterator struct {
+	ctx        context.Context
+	ms         *msgStore
+	getOptions *getOpts
+	started    bool      // set once the first batch has been read
+	done       bool      // set once the last batch has been read, or the iterator was closed
+	batch      []Message // what's left of the batch read last
+	current    Message
+	err        error
+}
+
+// Iterate reads the messages that meet the criteria specified in GetOption, like Get, but carries on past the batch size:
+// every batch read starts just after the last message in the one before (or just before it, reading Backward()), until
+// one comes back short. Last() and LastN() read a single batch, and UntilTime() stops at the time as usual.
+// Errors, including invalid options, are returned by the iterator's Err once Next returns false.
+func (ms *msgStore) Iterate(ctx context.Context, opts ...GetOption) MessageIterator {
+	it := &messageIterator{
+		ctx: ctx,
+		ms:  ms,
+	}
+
+	if len(opts) == 0 {
+		it.err = ErrMissingGetOptions
+		return it
+	}
+
+	getOptions, err := checkGetOptions(opts...)
+	if err == nil {
+		err = validateGetParams(getOptions)
+	}
+	it.getOptions = getOptions
+	it.err = err
+
+	return it
+}
+
+// Next moves on to the next message, reading another batch when the last one has been handed out
+func (it *messageIterator) Next() bool {
+	it.current = nil
+	for len(it.batch) == 0 {
+		if it.err != nil || it.done {
+			return false
+		}
+
+		it.err = it.read()
+	}
+
+	// the context is checked for every message, so whoever is iterating stops as soon as it's cancelled
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		it.batch = nil
+		return false
+	}
+
+	it.current = it.batch[0]
+	it.batch = it.batch[1:]
+	return true
+}
+
+// read reads the next batch, and moves the options on to where the batch after it starts
+func (it *messageIterator) read() error {
+	if err := it.ctx.Err(); err != nil {
+		return err
+	}
+
+	getOptions := it.getOptions
+	if !it.started {
+		it.started = true
+		if err := it.ms.resolveTimes(it.ctx, getOptions); err != nil {
+			return err
+		}
+	}
+
+	msgEnvelopes, err := it.ms.callCorrectRepositoryGetFunction(it.ctx, getOptions)
+	if err != nil {
+		return err
+	}
+	if err := it.ctx.Err(); err != nil {
+		return err // repositories may hand back an empty batch when the context is cancelled
+	}
+
+	trimmed := getOptions.trimUntil(msgEnvelopes)
+	it.batch = MsgEnvelopesToMessages(trimmed, getOptions.converters...)
+
+	// a short batch is the last one; batches are counted before conversion, as converters can drop messages
+	it.done = len(msgEnvelopes) == 0 ||
+		len(trimmed) < len(msgEnvelopes) ||
+		(getOptions.batchsize > 0 && len(msgEnvelopes) < getOptions.batchsize) ||
+		getOptions.last || getOptions.lastN > 0
+	if it.done {
+		return nil
+	}
+
+	last := msgEnvelopes[len(msgEnvelopes)-1]
+	next := last.GlobalPosition
+	if getOptions.stream != nil {
+		next = last.Version
+	}
+	if getOptions.backward {
+		next-- // backward reads include where they start, so start just before the last message read
+	} else {
+		next++ // so do forward reads, so start just after it
+	}
+	getOptions.since = &next
+
+	return nil
+}
+
+// Message gets the message Next moved on to
+func (it *messageIterator) Message() Message {
+	return it.current
+}
+
+// Err gets the error that stopped the iterator, if any
+func (it *messageIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator, dropping whatever is left of the batch it read last
+func (it *messageIterator) Close() error {
+	it.done = true
+	it.batch = nil
+	it.current = nil
+
+	return nil
+}
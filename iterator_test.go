@@ -0,0 +1,155 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/repository/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+)
+
+// positionsOf iterates to the end, returning the global position of every message
+func positionsOf(t *testing.T, msgs MessageIterator) []int64 {
+	defer msgs.Close()
+
+	positions := []int64{}
+	for msgs.Next() {
+		positions = append(positions, msgs.Message().Position())
+	}
+	if err := msgs.Err(); err != nil {
+		t.Errorf("An error has ocurred while iterating: %s", err)
+	}
+
+	return positions
+}
+
+func TestIteratePagesThroughCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+	ctx := context.Background()
+
+	logrusLogger := logrus.New()
+	logrusLogger.Out = ioutil.Discard
+
+	batch1 := getLotsOfSampleEventsAsEnvelopes(2, 0)
+	batch2 := getLotsOfSampleEventsAsEnvelopes(2, 2)
+	batch3 := getLotsOfSampleEventsAsEnvelopes(1, 4)
+	gomock.InOrder(
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInCategory(ctx, "test cat", 2).
+			Return(batch1, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInCategorySince(ctx, "test cat", batch1[1].GlobalPosition+1, 2).
+			Return(batch2, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInCategorySince(ctx, "test cat", batch2[1].GlobalPosition+1, 2).
+			Return(batch3, nil), // short, so it's the last batch
+	)
+
+	msgStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+	positions := positionsOf(t, msgStore.Iterate(ctx, Category("test cat"), BatchSize(2)))
+
+	expected := []int64{500, 501, 502, 503, 504}
+	if len(positions) != len(expected) {
+		t.Fatalf("Wrong messages iterated\nExpected: %v\n     Got: %v\n", expected, positions)
+	}
+	for i := range expected {
+		if positions[i] != expected[i] {
+			t.Errorf("Wrong messages iterated\nExpected: %v\n     Got: %v\n", expected, positions)
+		}
+	}
+}
+
+func TestIterateBackwardThroughStream(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	writeAccountEvents(msgStore, "Opened", "Deposited", "Deposited", "Withdrawn", "Closed")
+
+	msgs := msgStore.Iterate(ctx, EventStream("account", uuid1), Backward(), BatchSize(2))
+	defer msgs.Close()
+
+	versions := []int64{}
+	for msgs.Next() {
+		versions = append(versions, msgs.Message().Version())
+	}
+	if err := msgs.Err(); err != nil {
+		t.Fatalf("An error has ocurred while iterating: %s", err)
+	}
+
+	if len(versions) != 5 || versions[0] != 4 || versions[4] != 0 {
+		t.Errorf("Expected every version newest first, got %v", versions)
+	}
+}
+
+func TestIterateStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	writeAccountEvents(msgStore, "Opened", "Deposited", "Deposited")
+
+	msgs := msgStore.Iterate(ctx, Category("account"), BatchSize(2))
+	defer msgs.Close()
+
+	if !msgs.Next() {
+		t.Fatalf("Expected a first message, got error: %v", msgs.Err())
+	}
+	cancel()
+
+	if msgs.Next() {
+		t.Error("Expected iterating to stop once the context was cancelled")
+	}
+	if msgs.Err() != context.Canceled {
+		t.Errorf("Failed to get expected error from Err()\nExpected: %s\n and got: %v\n", context.Canceled, msgs.Err())
+	}
+}
+
+func TestIterateStopsWhenClosed(t *testing.T) {
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	writeAccountEvents(msgStore, "Opened", "Deposited", "Deposited")
+
+	msgs := msgStore.Iterate(context.Background(), Category("account"))
+	if !msgs.Next() {
+		t.Fatalf("Expected a first message, got error: %v", msgs.Err())
+	}
+	panicIf(msgs.Close())
+
+	if msgs.Next() || msgs.Message() != nil {
+		t.Error("Expected nothing more once the iterator was closed")
+	}
+	if msgs.Err() != nil {
+		t.Errorf("Expected no error from closing, got %s", msgs.Err())
+	}
+}
+
+func TestIterateLastNReadsOneBatch(t *testing.T) {
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	writeAccountEvents(msgStore, "Opened", "Deposited", "Deposited", "Closed")
+
+	positions := positionsOf(t, msgStore.Iterate(context.Background(), Category("account"), LastN(2)))
+
+	if len(positions) != 2 || positions[0] != 4 || positions[1] != 3 {
+		t.Errorf("Expected the last two messages, newest first, got %v", positions)
+	}
+}
+
+func TestIterateReturnsOptionErrors(t *testing.T) {
+	msgStore := NewMockMessageStoreWithMessages(nil)
+
+	msgs := msgStore.Iterate(context.Background())
+	if msgs.Next() || msgs.Err() != ErrMissingGetOptions {
+		t.Errorf("Failed to get expected error from Err()\nExpected: %s\n and got: %v\n", ErrMissingGetOptions, msgs.Err())
+	}
+
+	msgs = msgStore.Iterate(context.Background(), Category("account"), SinceVersion(3))
+	if msgs.Next() || msgs.Err() != ErrInvalidOptionCombination {
+		t.Errorf("Failed to get expected error from Err()\nExpected: %s\n and got: %v\n", ErrInvalidOptionCombination, msgs.Err())
+	}
+}
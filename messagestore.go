@@ -3,6 +3,7 @@ package gomessagestore
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/blackhatbrigade/gomessagestore/repository"
 	"github.com/blackhatbrigade/gomessagestore/repository/inmemory"
@@ -16,8 +17,16 @@ import (
 type MessageStore interface {
 	Write(ctx context.Context, message Message, opts ...WriteOption) error                                         // writes a message to the message store
 	Get(ctx context.Context, opts ...GetOption) ([]Message, error)                                                 // retrieves messages from the message store
+	Iterate(ctx context.Context, opts ...GetOption) MessageIterator                                                // retrieves messages from the message store a batch at a time, past the batch size
 	CreateProjector(opts ...ProjectorOption) (Projector, error)                                                    // creates a new projector
 	CreateSubscriber(subscriberID string, handlers []MessageHandler, opts ...SubscriberOption) (Subscriber, error) // creates a new subscriber
+	CreateEntityStore(projector Projector, opts ...EntityStoreOption) (EntityStore, error)                         // creates a new entity store
+	CreateProcessManager(processID string, opts ...ProcessManagerOption) (ProcessManager, error)                   // creates a new process manager
+	StreamVersion(ctx context.Context, streamName string) (int64, error)                                           // gets the version of the last message in a stream, -1 if there are none
+	StreamExists(ctx context.Context, streamName string) (bool, error)                                             // checks whether a stream has any messages
+	PositionAtTime(ctx context.Context, t time.Time, opts ...GetOption) (int64, error)                             // gets the position of the first message written at or after a time
+	Request(ctx context.Context, cmd Command, opts ...RequestOption) (Message, error)                              // writes a command and waits for its reply
+	WithTx(tx *sql.Tx) (MessageStore, error)                                                                       // gets a message store that reads and writes as part of the transaction
 	GetLogger() (logger logrus.FieldLogger)                                                                        // gets the logger
 }
 
@@ -61,6 +70,21 @@ func NewMockMessageStoreWithMessages(msgs []Message) MessageStore {
 	return NewMessageStoreFromRepository(r, logrus.New()) // passing in a log from the outside doesn't make sense here as we're just doing testing
 }
 
+// WithTx returns a message store that reads and writes as part of a transaction the caller began, so messages are only written
+// if the caller commits, alongside whatever else the transaction changed. Several writes through it commit (or roll back) together.
+// Only repositories that implement repository.TxRepository, like the postgres one, can do this.
+func (ms *msgStore) WithTx(tx *sql.Tx) (MessageStore, error) {
+	txRepo, ok := ms.repo.(repository.TxRepository)
+	if !ok {
+		return nil, ErrRepositoryDoesNotSupportTransactions
+	}
+
+	return &msgStore{
+		repo: txRepo.WithTx(tx),
+		log:  ms.log,
+	}, nil
+}
+
 // GetLogger gets the logger we need for other pieces
 func (ms *msgStore) GetLogger() logrus.FieldLogger {
 	if ms.log == nil {
@@ -93,3 +93,42 @@ func TestNewMockMessageStoreWithMessages(t *testing.T) {
 		}
 	}
 }
+
+func TestWithTxWritesThroughTheTransaction(t *testing.T) {
+	db, mockDb, _ := sqlmock.New()
+	msg := getSampleEvent()
+
+	mockDb.ExpectBegin()
+	mockDb.
+		ExpectExec("SELECT write_message\\(\\$1, \\$2, \\$3, \\$4, \\$5\\)").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDb.ExpectCommit()
+
+	var logrusLogger = logrus.New()
+	logrusLogger.Out = ioutil.Discard
+	msgStore := NewMessageStore(db, logrusLogger)
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	panicIf(err)
+
+	txStore, err := msgStore.WithTx(tx)
+	if err != nil {
+		t.Fatalf("Failed to bind message store to transaction: %s", err)
+	}
+	if err := txStore.Write(ctx, msg); err != nil {
+		t.Errorf("Failed to write through transaction: %s", err)
+	}
+	panicIf(tx.Commit())
+
+	if err := mockDb.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWithTxNeedsATransactionalRepository(t *testing.T) {
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	if _, err := msgStore.WithTx(nil); err != ErrRepositoryDoesNotSupportTransactions {
+		t.Errorf("Failed to get expected error from WithTx\nExpected: %s\n and got: %s\n", ErrRepositoryDoesNotSupportTransactions, err)
+	}
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/blackhatbrigade/gomessagestore (interfaces: EntityStore)
+
+// Package mock_gomessagestore is a generated GoMock package.
+package mock_gomessagestore
+
+import (
+	context "context"
+	uuid "github.com/blackhatbrigade/gomessagestore/uuid"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockEntityStore is a mock of EntityStore interface
+type MockEntityStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntityStoreMockRecorder
+}
+
+// MockEntityStoreMockRecorder is the mock recorder for MockEntityStore
+type MockEntityStoreMockRecorder struct {
+	mock *MockEntityStore
+}
+
+// NewMockEntityStore creates a new mock instance
+func NewMockEntityStore(ctrl *gomock.Controller) *MockEntityStore {
+	mock := &MockEntityStore{ctrl: ctrl}
+	mock.recorder = &MockEntityStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockEntityStore) EXPECT() *MockEntityStoreMockRecorder {
+	return m.recorder
+}
+
+// Fetch mocks base method
+func (m *MockEntityStore) Fetch(arg0 context.Context, arg1 string, arg2 uuid.UUID) (interface{}, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Fetch indicates an expected call of Fetch
+func (mr *MockEntityStoreMockRecorder) Fetch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockEntityStore)(nil).Fetch), arg0, arg1, arg2)
+}
+
+// FetchStream mocks base method
+func (m *MockEntityStore) FetchStream(arg0 context.Context, arg1 string) (interface{}, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchStream", arg0, arg1)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FetchStream indicates an expected call of FetchStream
+func (mr *MockEntityStoreMockRecorder) FetchStream(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchStream", reflect.TypeOf((*MockEntityStore)(nil).FetchStream), arg0, arg1)
+}
@@ -6,10 +6,12 @@ package mock_gomessagestore
 
 import (
 	context "context"
+	sql "database/sql"
 	gomessagestore "github.com/blackhatbrigade/gomessagestore"
 	gomock "github.com/golang/mock/gomock"
 	logrus "github.com/sirupsen/logrus"
 	reflect "reflect"
+	time "time"
 )
 
 // MockMessageStore is a mock of MessageStore interface
@@ -35,6 +37,46 @@ func (m *MockMessageStore) EXPECT() *MockMessageStoreMockRecorder {
 	return m.recorder
 }
 
+// CreateEntityStore mocks base method
+func (m *MockMessageStore) CreateEntityStore(arg0 gomessagestore.Projector, arg1 ...gomessagestore.EntityStoreOption) (gomessagestore.EntityStore, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateEntityStore", varargs...)
+	ret0, _ := ret[0].(gomessagestore.EntityStore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntityStore indicates an expected call of CreateEntityStore
+func (mr *MockMessageStoreMockRecorder) CreateEntityStore(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntityStore", reflect.TypeOf((*MockMessageStore)(nil).CreateEntityStore), varargs...)
+}
+
+// CreateProcessManager mocks base method
+func (m *MockMessageStore) CreateProcessManager(arg0 string, arg1 ...gomessagestore.ProcessManagerOption) (gomessagestore.ProcessManager, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateProcessManager", varargs...)
+	ret0, _ := ret[0].(gomessagestore.ProcessManager)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProcessManager indicates an expected call of CreateProcessManager
+func (mr *MockMessageStoreMockRecorder) CreateProcessManager(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProcessManager", reflect.TypeOf((*MockMessageStore)(nil).CreateProcessManager), varargs...)
+}
+
 // CreateProjector mocks base method
 func (m *MockMessageStore) CreateProjector(arg0 ...gomessagestore.ProjectorOption) (gomessagestore.Projector, error) {
 	m.ctrl.T.Helper()
@@ -108,6 +150,110 @@ func (mr *MockMessageStoreMockRecorder) GetLogger() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogger", reflect.TypeOf((*MockMessageStore)(nil).GetLogger))
 }
 
+// Iterate mocks base method
+func (m *MockMessageStore) Iterate(arg0 context.Context, arg1 ...gomessagestore.GetOption) gomessagestore.MessageIterator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Iterate", varargs...)
+	ret0, _ := ret[0].(gomessagestore.MessageIterator)
+	return ret0
+}
+
+// Iterate indicates an expected call of Iterate
+func (mr *MockMessageStoreMockRecorder) Iterate(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iterate", reflect.TypeOf((*MockMessageStore)(nil).Iterate), varargs...)
+}
+
+// PositionAtTime mocks base method
+func (m *MockMessageStore) PositionAtTime(arg0 context.Context, arg1 time.Time, arg2 ...gomessagestore.GetOption) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PositionAtTime", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PositionAtTime indicates an expected call of PositionAtTime
+func (mr *MockMessageStoreMockRecorder) PositionAtTime(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PositionAtTime", reflect.TypeOf((*MockMessageStore)(nil).PositionAtTime), varargs...)
+}
+
+// Request mocks base method
+func (m *MockMessageStore) Request(arg0 context.Context, arg1 gomessagestore.Command, arg2 ...gomessagestore.RequestOption) (gomessagestore.Message, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Request", varargs...)
+	ret0, _ := ret[0].(gomessagestore.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Request indicates an expected call of Request
+func (mr *MockMessageStoreMockRecorder) Request(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockMessageStore)(nil).Request), varargs...)
+}
+
+// StreamExists mocks base method
+func (m *MockMessageStore) StreamExists(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamExists", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamExists indicates an expected call of StreamExists
+func (mr *MockMessageStoreMockRecorder) StreamExists(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamExists", reflect.TypeOf((*MockMessageStore)(nil).StreamExists), arg0, arg1)
+}
+
+// StreamVersion mocks base method
+func (m *MockMessageStore) StreamVersion(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamVersion", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamVersion indicates an expected call of StreamVersion
+func (mr *MockMessageStoreMockRecorder) StreamVersion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamVersion", reflect.TypeOf((*MockMessageStore)(nil).StreamVersion), arg0, arg1)
+}
+
+// WithTx mocks base method
+func (m *MockMessageStore) WithTx(arg0 *sql.Tx) (gomessagestore.MessageStore, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(gomessagestore.MessageStore)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithTx indicates an expected call of WithTx
+func (mr *MockMessageStoreMockRecorder) WithTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockMessageStore)(nil).WithTx), arg0)
+}
+
 // Write mocks base method
 func (m *MockMessageStore) Write(arg0 context.Context, arg1 gomessagestore.Message, arg2 ...gomessagestore.WriteOption) error {
 	m.ctrl.T.Helper()
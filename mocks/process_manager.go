@@ -0,0 +1,48 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/blackhatbrigade/gomessagestore (interfaces: ProcessManager)
+
+// Package mock_gomessagestore is a generated GoMock package.
+package mock_gomessagestore
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockProcessManager is a mock of ProcessManager interface
+type MockProcessManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockProcessManagerMockRecorder
+}
+
+// MockProcessManagerMockRecorder is the mock recorder for MockProcessManager
+type MockProcessManagerMockRecorder struct {
+	mock *MockProcessManager
+}
+
+// NewMockProcessManager creates a new mock instance
+func NewMockProcessManager(ctrl *gomock.Controller) *MockProcessManager {
+	mock := &MockProcessManager{ctrl: ctrl}
+	mock.recorder = &MockProcessManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockProcessManager) EXPECT() *MockProcessManagerMockRecorder {
+	return m.recorder
+}
+
+// Start mocks base method
+func (m *MockProcessManager) Start(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start
+func (mr *MockProcessManagerMockRecorder) Start(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockProcessManager)(nil).Start), arg0)
+}
@@ -50,6 +50,21 @@ func (mr *MockProjectorMockRecorder) Run(arg0, arg1, arg2 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockProjector)(nil).Run), arg0, arg1, arg2)
 }
 
+// RunOnCategoryFrom mocks base method
+func (m *MockProjector) RunOnCategoryFrom(arg0 context.Context, arg1 string, arg2 interface{}, arg3 int64) (*gomessagestore.Projection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunOnCategoryFrom", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*gomessagestore.Projection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunOnCategoryFrom indicates an expected call of RunOnCategoryFrom
+func (mr *MockProjectorMockRecorder) RunOnCategoryFrom(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunOnCategoryFrom", reflect.TypeOf((*MockProjector)(nil).RunOnCategoryFrom), arg0, arg1, arg2, arg3)
+}
+
 // RunOnStream mocks base method
 func (m *MockProjector) RunOnStream(arg0 context.Context, arg1 string) (interface{}, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +80,51 @@ func (mr *MockProjectorMockRecorder) RunOnStream(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunOnStream", reflect.TypeOf((*MockProjector)(nil).RunOnStream), arg0, arg1)
 }
 
+// RunOnStreamFrom mocks base method
+func (m *MockProjector) RunOnStreamFrom(arg0 context.Context, arg1 string, arg2 interface{}, arg3 int64) (*gomessagestore.Projection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunOnStreamFrom", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*gomessagestore.Projection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunOnStreamFrom indicates an expected call of RunOnStreamFrom
+func (mr *MockProjectorMockRecorder) RunOnStreamFrom(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunOnStreamFrom", reflect.TypeOf((*MockProjector)(nil).RunOnStreamFrom), arg0, arg1, arg2, arg3)
+}
+
+// RunOnStreamWithVersion mocks base method
+func (m *MockProjector) RunOnStreamWithVersion(arg0 context.Context, arg1 string) (*gomessagestore.Projection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunOnStreamWithVersion", arg0, arg1)
+	ret0, _ := ret[0].(*gomessagestore.Projection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunOnStreamWithVersion indicates an expected call of RunOnStreamWithVersion
+func (mr *MockProjectorMockRecorder) RunOnStreamWithVersion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunOnStreamWithVersion", reflect.TypeOf((*MockProjector)(nil).RunOnStreamWithVersion), arg0, arg1)
+}
+
+// RunWithVersion mocks base method
+func (m *MockProjector) RunWithVersion(arg0 context.Context, arg1 string, arg2 uuid.UUID) (*gomessagestore.Projection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunWithVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*gomessagestore.Projection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunWithVersion indicates an expected call of RunWithVersion
+func (mr *MockProjectorMockRecorder) RunWithVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunWithVersion", reflect.TypeOf((*MockProjector)(nil).RunWithVersion), arg0, arg1, arg2)
+}
+
 // Step mocks base method
 func (m *MockProjector) Step(arg0 gomessagestore.Message, arg1 interface{}) (interface{}, bool, error) {
 	m.ctrl.T.Helper()
@@ -8,6 +8,7 @@ import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockSubscriber is a mock of Subscriber interface
@@ -33,6 +34,62 @@ func (m *MockSubscriber) EXPECT() *MockSubscriberMockRecorder {
 	return m.recorder
 }
 
+// Replay mocks base method
+func (m *MockSubscriber) Replay(arg0 context.Context, arg1, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replay", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replay indicates an expected call of Replay
+func (mr *MockSubscriberMockRecorder) Replay(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replay", reflect.TypeOf((*MockSubscriber)(nil).Replay), arg0, arg1, arg2)
+}
+
+// ResetPosition mocks base method
+func (m *MockSubscriber) ResetPosition(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPosition", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPosition indicates an expected call of ResetPosition
+func (mr *MockSubscriberMockRecorder) ResetPosition(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPosition", reflect.TypeOf((*MockSubscriber)(nil).ResetPosition), arg0, arg1)
+}
+
+// ResetToBeginning mocks base method
+func (m *MockSubscriber) ResetToBeginning(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetToBeginning", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetToBeginning indicates an expected call of ResetToBeginning
+func (mr *MockSubscriberMockRecorder) ResetToBeginning(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetToBeginning", reflect.TypeOf((*MockSubscriber)(nil).ResetToBeginning), arg0)
+}
+
+// ResetToTime mocks base method
+func (m *MockSubscriber) ResetToTime(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetToTime", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetToTime indicates an expected call of ResetToTime
+func (mr *MockSubscriberMockRecorder) ResetToTime(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetToTime", reflect.TypeOf((*MockSubscriber)(nil).ResetToTime), arg0, arg1)
+}
+
 // Start mocks base method
 func (m *MockSubscriber) Start(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,341 @@
+package gomessagestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+)
+
+//go:generate bash -c "${GOPATH}/bin/mockgen github.com/blackhatbrigade/gomessagestore ProcessManager > mocks/process_manager.go"
+
+// processStateChanged is the type of the event a process manager writes to a process's stream each time it handles a message
+const processStateChanged = "ProcessStateChanged"
+
+// ProcessManager coordinates a workflow that spans categories (a saga). Each process has its own stream, <category>-<correlation ID>,
+// holding its state as events. Messages are handed to the handler for their type along with the state of the process they belong to,
+// and the handler returns the commands to send and the next state.
+//
+// The next state is written as one event at the version the state was read at, so two instances handling messages for the same
+// process can't both move it on; the loser gets ErrExpectedVersionFailed and its subscriber tries the message again against the
+// new state. That event also records the commands the message caused, but it isn't written together with them: the commands are
+// written afterwards, one at a time, and nothing is rolled back if that's cut short. Instead the message is handed over again
+// (subscribers only move their position on after handling), finds itself already handled, and the commands it caused are sent
+// again with the IDs they were recorded with, skipping any that were already written. Commands are sent at least once this way,
+// but until the message is handed over again, the rest of them aren't sent at all.
+type ProcessManager interface {
+	Start(ctx context.Context) error // subscribes to the messages the process manager handles until the context is cancelled
+}
+
+// ProcessHandlerFunc handles a message for a process, returning the commands to send and the next state of the process.
+// The state is decoded from JSON into the type of ProcessState's default state, so the next state should be that type too.
+type ProcessHandlerFunc func(ctx context.Context, state interface{}, msg Message) (commands []Command, nextState interface{}, err error)
+
+// CorrelationFunc picks the process a message belongs to
+type CorrelationFunc func(msg Message) (uuid.UUID, error)
+
+// ProcessManagerOption is used for creating process managers
+type ProcessManagerOption func(pm *processManager)
+
+type processManager struct {
+	ms             MessageStore
+	processID      string
+	category       string                        // the category of every process's state stream
+	defaultState   interface{}                   // the state of a process before it has handled anything
+	handlers       map[string]ProcessHandlerFunc // by message type
+	correlate      CorrelationFunc
+	subscriberOpts []SubscriberOption
+	projector      Projector
+	subscriber     Subscriber
+}
+
+// processRecord is what's written to a process's stream each time it moves on
+type processRecord struct {
+	State    json.RawMessage  `json:"state"`
+	Message  uuid.UUID        `json:"message"`  // the ID of the message that moved the process on
+	Position int64            `json:"position"` // the global position of the message that moved the process on
+	Commands []processCommand `json:"commands"` // the commands sent as a result
+}
+
+// processCommand is a command waiting to be sent, as it's kept in a processRecord
+type processCommand struct {
+	ID             uuid.UUID       `json:"id"`
+	EntityID       uuid.UUID       `json:"entityId"`
+	StreamCategory string          `json:"streamCategory"`
+	MessageType    string          `json:"messageType"`
+	Data           json.RawMessage `json:"data"`
+	Metadata       json.RawMessage `json:"metadata,omitempty"`
+}
+
+// processState is a process as projected from its stream
+type processState struct {
+	state    interface{}
+	position int64                   // messages at or before this global position have already been handled
+	commands map[uuid.UUID][]Command // the commands each message handled caused, by the message's ID
+}
+
+// CreateProcessManager creates a new ProcessManager; processID is used as the ID of its subscriber
+func (ms *msgStore) CreateProcessManager(processID string, opts ...ProcessManagerOption) (ProcessManager, error) {
+	pm := &processManager{
+		ms:        ms,
+		processID: processID,
+		handlers:  make(map[string]ProcessHandlerFunc),
+		correlate: correlateByEntityID,
+	}
+
+	for _, option := range opts {
+		option(pm)
+	}
+
+	if pm.category == "" {
+		return nil, ErrProcessManagerNeedsCategory
+	}
+	if strings.Contains(pm.category, "-") {
+		return nil, ErrInvalidMessageCategory
+	}
+	if pm.defaultState == nil {
+		return nil, ErrDefaultStateNotSet
+	}
+	if reflect.ValueOf(pm.defaultState).Kind() == reflect.Ptr {
+		return nil, ErrDefaultStateCannotBePointer
+	}
+	if len(pm.handlers) < 1 {
+		return nil, ErrProcessManagerNeedsAtLeastOneHandler
+	}
+
+	projector, err := ms.CreateProjector(
+		DefaultState(processState{state: pm.defaultState, position: -1}),
+		WithReducerFunc(processStateChanged, pm.reduce),
+	)
+	if err != nil {
+		return nil, err
+	}
+	pm.projector = projector
+
+	handlers := make([]MessageHandler, 0, len(pm.handlers))
+	for msgType := range pm.handlers {
+		handlers = append(handlers, &processMessageHandler{pm: pm, msgType: msgType})
+	}
+
+	pm.subscriber, err = ms.CreateSubscriber(processID, handlers, pm.subscriberOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pm, nil
+}
+
+// ProcessCategory sets the category of the streams each process's state is kept in
+func ProcessCategory(category string) ProcessManagerOption {
+	return func(pm *processManager) {
+		pm.category = category
+	}
+}
+
+// ProcessState sets the state a process starts in; like DefaultState, it cannot be a pointer
+func ProcessState(defaultState interface{}) ProcessManagerOption {
+	return func(pm *processManager) {
+		pm.defaultState = defaultState
+	}
+}
+
+// WithProcessHandler registers the handler for a message type
+func WithProcessHandler(msgType string, handler ProcessHandlerFunc) ProcessManagerOption {
+	return func(pm *processManager) {
+		pm.handlers[msgType] = handler
+	}
+}
+
+// ProcessCorrelation sets how a message is matched to its process; by default the message's entity ID is used
+func ProcessCorrelation(correlate CorrelationFunc) ProcessManagerOption {
+	return func(pm *processManager) {
+		pm.correlate = correlate
+	}
+}
+
+// ProcessSubscriberOptions sets the options of the subscriber feeding the process manager, including what it subscribes to
+func ProcessSubscriberOptions(opts ...SubscriberOption) ProcessManagerOption {
+	return func(pm *processManager) {
+		pm.subscriberOpts = append(pm.subscriberOpts, opts...)
+	}
+}
+
+// Start subscribes to the messages the process manager handles until the context is cancelled
+func (pm *processManager) Start(ctx context.Context) error {
+	return pm.subscriber.Start(ctx)
+}
+
+// handle moves the process a message belongs to on
+func (pm *processManager) handle(ctx context.Context, msg Message) error {
+	correlationID, err := pm.correlate(msg)
+	if err != nil {
+		return err
+	}
+	if correlationID == NilUUID {
+		return ErrMissingCorrelationID
+	}
+	stream := pm.category + "-" + correlationID.String()
+
+	projection, err := pm.projector.RunOnStreamWithVersion(ctx, stream)
+	if err != nil {
+		return err
+	}
+	process := projection.State.(processState)
+
+	envelope, err := msg.ToEnvelope()
+	if err != nil {
+		return err
+	}
+
+	if msg.Position() <= process.position {
+		// already handled, but the commands it caused may not have made it out before whoever handled it stopped
+		return pm.send(ctx, process.commands[envelope.ID])
+	}
+
+	commands, nextState, err := pm.handlers[msg.Type()](ctx, process.state, msg)
+	if err != nil {
+		return err
+	}
+
+	commands, err = causedByMessage(commands, envelope.ID)
+	if err != nil {
+		return err
+	}
+
+	event, err := pm.stateChanged(correlationID, nextState, envelope.ID, msg.Position(), commands)
+	if err != nil {
+		return err
+	}
+
+	if err := pm.ms.Write(ctx, event, AtPosition(projection.Version)); err != nil {
+		return err
+	}
+
+	return pm.send(ctx, commands)
+}
+
+// send writes commands, skipping any that have already been written
+func (pm *processManager) send(ctx context.Context, commands []Command) error {
+	for _, cmd := range commands {
+		if err := pm.ms.Write(ctx, cmd); err != nil && !errors.Is(err, ErrDuplicateMessageID) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// stateChanged makes the event that moves a process on
+func (pm *processManager) stateChanged(correlationID uuid.UUID, nextState interface{}, msgID uuid.UUID, position int64, commands []Command) (Event, error) {
+	state, err := json.Marshal(nextState)
+	if err != nil {
+		return Event{}, err
+	}
+
+	record := processRecord{
+		State:    state,
+		Message:  msgID,
+		Position: position,
+		Commands: make([]processCommand, len(commands)),
+	}
+	for i, cmd := range commands {
+		if _, err := cmd.ToEnvelope(); err != nil {
+			return Event{}, err // don't let a command that can't be sent into the process's history
+		}
+		record.Commands[i] = processCommand{
+			ID:             cmd.ID,
+			EntityID:       cmd.EntityID,
+			StreamCategory: cmd.StreamCategory,
+			MessageType:    cmd.MessageType,
+			Data:           cmd.Data,
+			Metadata:       cmd.Metadata,
+		}
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return NewEvent(NewID(), correlationID, pm.category, processStateChanged, data, nil), nil
+}
+
+// reduce rebuilds a process's state from one of its events
+func (pm *processManager) reduce(msg Message, previousState interface{}) (interface{}, error) {
+	previous := previousState.(processState)
+
+	event, ok := msg.(Event)
+	if !ok {
+		return nil, ErrInvalidProcessState
+	}
+
+	var record processRecord
+	if err := json.Unmarshal(event.Data, &record); err != nil {
+		return nil, err
+	}
+
+	state := reflect.New(reflect.TypeOf(pm.defaultState))
+	if err := json.Unmarshal(record.State, state.Interface()); err != nil {
+		return nil, err
+	}
+
+	commands := make([]Command, len(record.Commands))
+	for i, cmd := range record.Commands {
+		commands[i] = NewCommand(cmd.ID, cmd.EntityID, cmd.StreamCategory, cmd.MessageType, cmd.Data, cmd.Metadata)
+	}
+
+	sent := previous.commands
+	if sent == nil {
+		sent = make(map[uuid.UUID][]Command) // the default state's map is never changed, every run gets its own
+	}
+	sent[record.Message] = commands
+
+	return processState{
+		state:    state.Elem().Interface(),
+		position: record.Position,
+		commands: sent,
+	}, nil
+}
+
+// causedByMessage points each command's metadata back at the message that caused it
+func causedByMessage(commands []Command, msgID uuid.UUID) ([]Command, error) {
+	var err error
+	caused := make([]Command, len(commands))
+	for i, cmd := range commands {
+		if cmd.Metadata, err = withMetadataField(cmd.Metadata, causationMessageKey, msgID); err != nil {
+			return nil, err
+		}
+		caused[i] = cmd
+	}
+
+	return caused, nil
+}
+
+// correlateByEntityID puts a message in the process named by its entity ID
+func correlateByEntityID(msg Message) (uuid.UUID, error) {
+	switch message := msg.(type) {
+	case Event:
+		return message.EntityID, nil
+	case Command:
+		return message.EntityID, nil
+	}
+
+	return NilUUID, ErrMissingCorrelationID
+}
+
+// processMessageHandler feeds the process manager's subscriber's messages of one type to the process manager
+type processMessageHandler struct {
+	pm      *processManager
+	msgType string
+}
+
+func (handler *processMessageHandler) Type() string {
+	return handler.msgType
+}
+
+func (handler *processMessageHandler) Process(ctx context.Context, msg Message) error {
+	return handler.pm.handle(ctx, msg)
+}
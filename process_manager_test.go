@@ -0,0 +1,300 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+)
+
+type orderProcess struct {
+	Step     string
+	Attempts int
+}
+
+// waitFor checks condition until it's true, failing the test if that takes too long
+func waitFor(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the process manager")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// countMessages counts the messages in a stream
+func countMessages(t *testing.T, msgStore MessageStore, stream string) int {
+	msgs, err := msgStore.Get(context.Background(), GenericStream(stream))
+	if err != nil {
+		t.Fatalf("Failed to read %s: %s", stream, err)
+	}
+
+	return len(msgs)
+}
+
+// startProcessManager starts a process manager, returning the function that stops it
+func startProcessManager(t *testing.T, msgStore MessageStore, processID string, opts ...ProcessManagerOption) func() {
+	opts = append(opts, ProcessSubscriberOptions(
+		SubscribeToCategory("order"),
+		SubscribeToCategory("payment"),
+		PollTime(time.Millisecond),
+		PollErrorDelay(time.Millisecond),
+		UpdatePositionEvery(2),
+	))
+
+	pm, err := msgStore.CreateProcessManager(processID, opts...)
+	if err != nil {
+		t.Fatalf("Failed to create process manager: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		pm.Start(ctx)
+		close(stopped)
+	}()
+
+	return func() {
+		cancel()
+		<-stopped
+	}
+}
+
+// orderHandlers asks for payment when an order is placed and ships it once paid for
+func orderHandlers(calls *int, mutex *sync.Mutex) []ProcessManagerOption {
+	return []ProcessManagerOption{
+		ProcessCategory("orderProcess"),
+		ProcessState(orderProcess{Step: "new"}),
+		WithProcessHandler("OrderPlaced", func(ctx context.Context, state interface{}, msg Message) ([]Command, interface{}, error) {
+			mutex.Lock()
+			*calls++
+			mutex.Unlock()
+
+			process := state.(orderProcess)
+			process.Step = "awaitingPayment"
+			process.Attempts++
+			evt := msg.(Event)
+			return []Command{NewCommand(NewID(), evt.EntityID, "payment", "ReservePayment", []byte(`{}`), nil)}, process, nil
+		}),
+		WithProcessHandler("PaymentReserved", func(ctx context.Context, state interface{}, msg Message) ([]Command, interface{}, error) {
+			mutex.Lock()
+			*calls++
+			mutex.Unlock()
+
+			process := state.(orderProcess)
+			if process.Step != "awaitingPayment" {
+				return nil, process, nil
+			}
+			process.Step = "shipping"
+			evt := msg.(Event)
+			return []Command{NewCommand(NewID(), evt.EntityID, "shipping", "ShipOrder", []byte(`{}`), nil)}, process, nil
+		}),
+	}
+}
+
+func TestProcessManagerRunsAProcess(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMockMessageStoreWithMessages(nil)
+
+	var calls int
+	var mutex sync.Mutex
+	stop := startProcessManager(t, msgStore, "orderProcessor", orderHandlers(&calls, &mutex)...)
+
+	panicIf(msgStore.Write(ctx, NewEvent(NewID(), uuid1, "order", "OrderPlaced", []byte(`{}`), nil)))
+	waitFor(t, func() bool {
+		return countMessages(t, msgStore, "payment:command-"+uuid1.String()) == 1
+	})
+
+	panicIf(msgStore.Write(ctx, NewEvent(NewID(), uuid1, "payment", "PaymentReserved", []byte(`{}`), nil)))
+	waitFor(t, func() bool {
+		return countMessages(t, msgStore, "shipping:command-"+uuid1.String()) == 1
+	})
+	stop()
+
+	msgs, err := msgStore.Get(ctx, GenericStream("orderProcess-"+uuid1.String()))
+	panicIf(err)
+	if len(msgs) != 2 {
+		t.Fatalf("Expected 2 state changes, got %d", len(msgs))
+	}
+
+	var record struct {
+		State orderProcess
+	}
+	panicIf(json.Unmarshal(msgs[1].(Event).Data, &record))
+	if record.State != (orderProcess{Step: "shipping", Attempts: 1}) {
+		t.Errorf("Process ended in the wrong state: %+v", record.State)
+	}
+
+	commands, err := msgStore.Get(ctx, GenericStream("shipping:command-"+uuid1.String()))
+	panicIf(err)
+	var metadata map[string]uuid.UUID
+	panicIf(json.Unmarshal(commands[0].(Command).Metadata, &metadata))
+	if metadata["causationMessageId"] == NilUUID {
+		t.Errorf("Command does not point back at the message that caused it: %s", commands[0].(Command).Metadata)
+	}
+
+	// a second process manager reading the same messages from the beginning finds them already handled
+	stop = startProcessManager(t, msgStore, "lateOrderProcessor", orderHandlers(&calls, &mutex)...)
+	waitFor(t, func() bool {
+		return countMessages(t, msgStore, "lateOrderProcessor+position") > 0
+	})
+	stop()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if calls != 2 {
+		t.Errorf("Expected each message to be handled once, got %d calls", calls)
+	}
+	if count := countMessages(t, msgStore, "orderProcess-"+uuid1.String()); count != 2 {
+		t.Errorf("Expected 2 state changes, got %d", count)
+	}
+}
+
+func TestProcessManagerInstancesDoNotClobberEachOther(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	panicIf(msgStore.Write(ctx, NewEvent(NewID(), uuid1, "order", "OrderPlaced", []byte(`{}`), nil)))
+
+	// the slow instance has read the process's state before the fast one starts, and doesn't finish handling until the fast one has moved the process on
+	var calls, attempts int
+	var mutex sync.Mutex
+	fast := func() {}
+	defer func() { fast() }()
+
+	slow := startProcessManager(t, msgStore, "slowProcessor",
+		ProcessCategory("orderProcess"),
+		ProcessState(orderProcess{}),
+		ProcessCorrelation(func(msg Message) (uuid.UUID, error) {
+			mutex.Lock()
+			attempts++
+			mutex.Unlock()
+			return msg.(Event).EntityID, nil
+		}),
+		WithProcessHandler("OrderPlaced", func(ctx context.Context, state interface{}, msg Message) ([]Command, interface{}, error) {
+			fast = startProcessManager(t, msgStore, "fastProcessor", orderHandlers(&calls, &mutex)...)
+			for {
+				if exists, err := msgStore.StreamExists(ctx, "orderProcess-"+uuid1.String()); err == nil && exists {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			process := state.(orderProcess)
+			process.Step = "slow"
+			return []Command{NewCommand(NewID(), uuid1, "payment", "ReservePayment", []byte(`{}`), nil)}, process, nil
+		}),
+	)
+	defer slow()
+
+	// the first attempt loses the race, the second finds the message already handled
+	waitFor(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return attempts >= 2
+	})
+
+	if count := countMessages(t, msgStore, "orderProcess-"+uuid1.String()); count != 1 {
+		t.Errorf("Expected 1 state change, got %d", count)
+	}
+	if count := countMessages(t, msgStore, "payment:command-"+uuid1.String()); count != 1 {
+		t.Errorf("Expected 1 command, got %d", count)
+	}
+}
+
+func TestProcessManagerResendsOnlyTheRedeliveredMessagesCommands(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	orderPlaced := NewEvent(NewID(), uuid1, "order", "OrderPlaced", []byte(`{}`), nil)
+	paymentReserved := NewEvent(NewID(), uuid1, "payment", "PaymentReserved", []byte(`{}`), nil)
+	panicIf(msgStore.Write(ctx, orderPlaced))
+	panicIf(msgStore.Write(ctx, paymentReserved))
+
+	// both messages moved the process on, but whoever handled them stopped before sending any commands
+	stateChanged := func(msgID uuid.UUID, position int64, category, msgType string) {
+		data, err := json.Marshal(map[string]interface{}{
+			"state":    orderProcess{Step: msgType},
+			"message":  msgID,
+			"position": position,
+			"commands": []map[string]interface{}{{
+				"id":             NewID(),
+				"entityId":       uuid1,
+				"streamCategory": category,
+				"messageType":    msgType,
+				"data":           json.RawMessage(`{}`),
+			}},
+		})
+		panicIf(err)
+		panicIf(msgStore.Write(ctx, NewEvent(NewID(), uuid1, "orderProcess", "ProcessStateChanged", data, nil)))
+	}
+	stateChanged(orderPlaced.ID, 1, "payment", "ReservePayment")
+	stateChanged(paymentReserved.ID, 2, "shipping", "ShipOrder")
+
+	var calls int
+	var mutex sync.Mutex
+	stop := startProcessManager(t, msgStore, "orderProcessor", orderHandlers(&calls, &mutex)...)
+	defer stop()
+
+	waitFor(t, func() bool {
+		return countMessages(t, msgStore, "payment:command-"+uuid1.String()) == 1 &&
+			countMessages(t, msgStore, "shipping:command-"+uuid1.String()) == 1
+	})
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if calls != 0 {
+		t.Errorf("Expected both messages to be found already handled, but the handlers were called %d times", calls)
+	}
+}
+
+func TestCreateProcessManagerErrors(t *testing.T) {
+	handler := WithProcessHandler("OrderPlaced", func(ctx context.Context, state interface{}, msg Message) ([]Command, interface{}, error) {
+		return nil, state, nil
+	})
+	subscription := ProcessSubscriberOptions(SubscribeToCategory("order"))
+
+	tests := []struct {
+		name          string
+		expectedError error
+		opts          []ProcessManagerOption
+	}{{
+		name:          "a category is needed",
+		expectedError: ErrProcessManagerNeedsCategory,
+		opts:          []ProcessManagerOption{ProcessState(orderProcess{}), handler, subscription},
+	}, {
+		name:          "the category cannot contain a hyphen",
+		expectedError: ErrInvalidMessageCategory,
+		opts:          []ProcessManagerOption{ProcessCategory("order-process"), ProcessState(orderProcess{}), handler, subscription},
+	}, {
+		name:          "a default state is needed",
+		expectedError: ErrDefaultStateNotSet,
+		opts:          []ProcessManagerOption{ProcessCategory("orderProcess"), handler, subscription},
+	}, {
+		name:          "the default state cannot be a pointer",
+		expectedError: ErrDefaultStateCannotBePointer,
+		opts:          []ProcessManagerOption{ProcessCategory("orderProcess"), ProcessState(&orderProcess{}), handler, subscription},
+	}, {
+		name:          "a handler is needed",
+		expectedError: ErrProcessManagerNeedsAtLeastOneHandler,
+		opts:          []ProcessManagerOption{ProcessCategory("orderProcess"), ProcessState(orderProcess{}), subscription},
+	}, {
+		name:          "a subscription is needed",
+		expectedError: ErrSubscriberNeedsCategoryOrStream,
+		opts:          []ProcessManagerOption{ProcessCategory("orderProcess"), ProcessState(orderProcess{}), handler},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msgStore := NewMockMessageStoreWithMessages(nil)
+
+			_, err := msgStore.CreateProcessManager("orderProcessor", test.opts...)
+			if err != test.expectedError {
+				t.Errorf("Failed to get expected error from CreateProcessManager\nExpected: %s\n and got: %s\n", test.expectedError, err)
+			}
+		})
+	}
+}
@@ -28,10 +28,24 @@ func (ms *msgStore) CreateProjector(opts ...ProjectorOption) (Projector, error)
 		return nil, ErrProjectorNeedsAtLeastOneReducer
 	}
 
-	if projector.defaultState == nil {
+	if projector.defaultState != nil && projector.stateFactory != nil {
+		return nil, ErrDefaultStateAndStateFactory
+	}
+
+	if projector.defaultState == nil && projector.stateFactory == nil {
 		return nil, ErrDefaultStateNotSet
 	}
 
+	// keep our own copy of the reducers so nothing can change them once runs are underway
+	projector.reducers = append([]MessageReducer(nil), projector.reducers...)
+
+	// strict mode and OnUnhandled need to see the messages without a reducer, otherwise only the types with one are read
+	if !projector.strict && projector.onUnhandled == nil {
+		for _, reducer := range projector.reducers {
+			projector.types = append(projector.types, reducer.Type())
+		}
+	}
+
 	return projector, nil
 }
 
@@ -39,17 +53,41 @@ func (ms *msgStore) CreateProjector(opts ...ProjectorOption) (Projector, error)
 type ProjectorOption func(proj *projector)
 
 // Projector A base level interface that defines the projection functionality of gomessagestore.
+//
+// A Projector is safe for concurrent use by multiple goroutines: nothing about it changes once it has been created, and each
+// run keeps its state to itself. The one thing runs can share is the starting state. A DefaultState is handed to every run as is,
+// so reducers must return an updated copy rather than changing any maps, slices or pointers inside it. Use a StateFactory when
+// reducers need to change the state in place (or the state is a pointer) so every run starts from a state of its own.
+//
+// An error from a reducer is never returned as is: it comes back wrapped in a *ProjectionError pointing at the message that
+// broke the projection. Code that compared a run's error with a reducer's error using == has to use errors.Is instead.
 type Projector interface {
 	Run(ctx context.Context, category string, entityID uuid.UUID) (interface{}, error)
 	RunOnStream(ctx context.Context, stream string) (interface{}, error)
+	RunWithVersion(ctx context.Context, category string, entityID uuid.UUID) (*Projection, error)
+	RunOnStreamWithVersion(ctx context.Context, stream string) (*Projection, error)
+	RunOnStreamFrom(ctx context.Context, stream string, previousState interface{}, version int64) (*Projection, error)
+	RunOnCategoryFrom(ctx context.Context, category string, previousState interface{}, position int64) (*Projection, error)
 	Step(msg Message, previousState interface{}) (interface{}, bool, error)
 }
 
+// Projection is the state derived by a projector along with how far into the stream it got
+type Projection struct {
+	State   interface{} // the state after all messages were run through the reducers
+	Version int64       // the version of the stream when it was projected, even when its last messages have no reducer, -1 when the stream has no messages; can be passed to AtPosition. For category projections this is the global position of the last message read
+	Exists  bool        // false when the stream (or category) has no messages at all
+}
+
 // projector The base projector struct.
 type projector struct {
 	ms           MessageStore
 	reducers     []MessageReducer
 	defaultState interface{}
+	stateFactory func() interface{}
+	strict       bool              // when set, messages without a reducer (that aren't ignored) fail the projection
+	ignoredTypes map[string]bool   // message types that are expected to have no reducer
+	onUnhandled  func(msg Message) // called for each message without a reducer (that isn't ignored)
+	types        []string          // the only message types read, when set
 }
 
 // RunOnStream retrieves all messages for a given stream, and runs the projector on each message found
@@ -62,37 +100,153 @@ func (proj *projector) Run(ctx context.Context, category string, entityID uuid.U
 	return proj.run(ctx, category+"-"+entityID.String())
 }
 
+// RunOnStreamWithVersion retrieves all messages for a given stream, runs the projector on each message found, and returns the state along with the version of the stream
+func (proj *projector) RunOnStreamWithVersion(ctx context.Context, stream string) (*Projection, error) {
+	return proj.runWithVersion(ctx, stream)
+}
+
+// RunWithVersion retrieves all messages for a given category and entity, runs the projector on each message found, and returns the state along with the version of the stream
+func (proj *projector) RunWithVersion(ctx context.Context, category string, entityID uuid.UUID) (*Projection, error) {
+	return proj.runWithVersion(ctx, category+"-"+entityID.String())
+}
+
+// runWithVersion projects a whole stream, keeping track of the version of the last message read so the caller can write at that version
+func (proj *projector) runWithVersion(ctx context.Context, stream string) (*Projection, error) {
+	state, err := proj.initialState()
+	if err != nil {
+		return nil, err
+	}
+
+	return proj.RunOnStreamFrom(ctx, stream, state, -1)
+}
+
+// RunOnStreamFrom catches previousState up by running only the messages in the stream after version through the reducers
+// version should be the version of the last message already applied to previousState (see Projection.Version), or -1 to start at the beginning of the stream
+func (proj *projector) RunOnStreamFrom(ctx context.Context, stream string, previousState interface{}, version int64) (*Projection, error) {
+	streamVersion := int64(-1)
+	if len(proj.types) > 0 {
+		// the last message read may not be the last in the stream when only some types are read, so the stream's version is read too;
+		// reading it first means a message written in between makes writes at the version fail rather than go unprojected
+		var err error
+		if streamVersion, err = proj.ms.StreamVersion(ctx, stream); err != nil {
+			return nil, err
+		}
+	}
+
+	state, version, err := proj.project(ctx, stream, previousState, version)
+	if err != nil {
+		return nil, err
+	}
+	if streamVersion > version {
+		version = streamVersion
+	}
+
+	return &Projection{
+		State:   state,
+		Version: version,
+		Exists:  version >= 0,
+	}, nil
+}
+
+// RunOnCategoryFrom folds every message in a category after the global position through the reducers, starting from previousState; useful for building read models that aggregate a whole category
+// position should be the global position of the last message already applied to previousState (see Projection.Version), or -1 to start at the beginning of the category
+func (proj *projector) RunOnCategoryFrom(ctx context.Context, category string, previousState interface{}, position int64) (*Projection, error) {
+	msgs := proj.iterate(ctx, Category(category), SincePosition, position)
+	state, position, err := proj.fold(msgs, previousState, position, Message.Position)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Projection{
+		State:   state,
+		Version: position,
+		Exists:  position >= 0,
+	}, nil
+}
+
 // run calls getMessages, for a given category and id, on the projector and runs each message through a matching reducer to derive the state, and returns the state after all messages are processed
 func (proj *projector) run(ctx context.Context, stream string) (interface{}, error) {
-	msgs, err := proj.getMessages(ctx, stream)
+	state, err := proj.initialState()
+	if err != nil {
+		return nil, err
+	}
 
+	state, _, err = proj.project(ctx, stream, state, -1)
 	if err != nil {
 		return nil, err
 	}
 
-	state := proj.defaultState
-	for _, message := range msgs {
+	return state, nil
+}
+
+// initialState gives each run its starting state, calling the state factory when there is one
+func (proj *projector) initialState() (interface{}, error) {
+	if proj.stateFactory == nil {
+		return proj.defaultState, nil
+	}
+
+	state := proj.stateFactory()
+	if state == nil {
+		return nil, ErrStateFactoryReturnedNil
+	}
+
+	return state, nil
+}
+
+// project runs every message in the stream after the provided version through the reducers, starting from the provided state; a version of -1 projects the whole stream
+// it returns the new state along with the version of the last message read from the stream (or the provided version if nothing new was found)
+func (proj *projector) project(ctx context.Context, stream string, state interface{}, version int64) (interface{}, int64, error) {
+	msgs := proj.iterate(ctx, GenericStream(stream), SinceVersion, version)
+
+	return proj.fold(msgs, state, version, Message.Version)
+}
+
+// fold steps through each message, returning the final state and the cursor (version or position) of the last message
+func (proj *projector) fold(msgs MessageIterator, state interface{}, cursor int64, cursorOf func(Message) int64) (interface{}, int64, error) {
+	defer msgs.Close()
+
+	for msgs.Next() {
+		message := msgs.Message()
 		if newState, ok, err := proj.Step(message, state); err != nil {
-			return nil, err
+			return nil, cursor, err
 		} else if ok {
 			state = newState
 		}
+		cursor = cursorOf(message)
 	}
 
-	return state, nil
+	if err := msgs.Err(); err != nil {
+		return nil, cursor, err
+	}
+
+	return state, cursor, nil
 }
 
 // Step is ran for each message, iterating the state for the reducer mapped to that message
+// errors from reducers, and messages without a reducer in strict mode, are returned as a *ProjectionError
 func (proj *projector) Step(msg Message, previousState interface{}) (interface{}, bool, error) {
 	for _, reducer := range proj.reducers {
 		if reducer.Type() == msg.Type() {
 			if reduction, err := reducer.Reduce(msg, previousState); err == nil {
 				return reduction, true, nil
 			} else {
-				return nil, false, err
+				return nil, false, newProjectionError(msg, err)
 			}
 		}
 	}
+
+	if proj.ignoredTypes[msg.Type()] {
+		return nil, false, nil
+	}
+
+	if proj.onUnhandled != nil {
+		proj.onUnhandled(msg)
+	}
+
+	if proj.strict {
+		return nil, false, newProjectionError(msg, ErrUnhandledMessageType)
+	}
+
 	return nil, false, nil
 }
 
@@ -111,42 +265,56 @@ func WithReducerFunc(msgType string, reducerFunc MessageReducerFunc) ProjectorOp
 	}
 }
 
-//DefaultState registers a default state for use with a projector
+//DefaultState registers a default state for use with a projector; the same value starts every run, so it cannot be a pointer
 func DefaultState(defaultState interface{}) ProjectorOption {
 	return func(proj *projector) {
 		proj.defaultState = defaultState
 	}
 }
 
-// getMessages retrieves messages from the message store
-func (proj *projector) getMessages(ctx context.Context, stream string) ([]Message, error) {
-	batchsize := 1000
-	msgs, err := proj.ms.Get(ctx,
-		GenericStream(stream),
-		BatchSize(batchsize),
-	)
-	if err != nil {
-		return nil, err
+//StateFactory registers a function that creates a fresh starting state for every run of a projector; unlike DefaultState the state can be a pointer, or hold maps and slices that reducers change in place
+func StateFactory(factory func() interface{}) ProjectorOption {
+	return func(proj *projector) {
+		proj.stateFactory = factory
 	}
+}
 
-	if len(msgs) == batchsize {
-		allMsgs := make([]Message, 0, batchsize*2)
-		allMsgs = append(allMsgs, msgs...)
-		for len(msgs) == batchsize {
-			msgs, err = proj.ms.Get(ctx,
-				GenericStream(stream),
-				BatchSize(batchsize),
-				SinceVersion(msgs[batchsize-1].Version()+1), // Since grabs an inclusive list, so grab 1 after the latest version
-			)
-			if err != nil {
-				return nil, err
-			}
+//StrictMode makes the projector fail with ErrUnhandledMessageType when it comes across a message that has no reducer and isn't ignored
+func StrictMode() ProjectorOption {
+	return func(proj *projector) {
+		proj.strict = true
+	}
+}
 
-			allMsgs = append(allMsgs, msgs...)
+//IgnoreTypes registers message types that are expected to have no reducer, so they pass through strict mode and OnUnhandled silently
+func IgnoreTypes(msgTypes ...string) ProjectorOption {
+	return func(proj *projector) {
+		if proj.ignoredTypes == nil {
+			proj.ignoredTypes = make(map[string]bool)
+		}
+		for _, msgType := range msgTypes {
+			proj.ignoredTypes[msgType] = true
 		}
+	}
+}
 
-		return allMsgs, nil
+//OnUnhandled registers a function that is called with each message that has no reducer and isn't ignored
+func OnUnhandled(unhandledFunc func(msg Message)) ProjectorOption {
+	return func(proj *projector) {
+		proj.onUnhandled = unhandledFunc
+	}
+}
+
+// iterate reads the messages after the cursor (a version or global position) a batch at a time; a cursor of -1 reads from the beginning
+func (proj *projector) iterate(ctx context.Context, from GetOption, since func(int64) GetOption, cursor int64) MessageIterator {
+	opts := []GetOption{
+		from,
+		BatchSize(1000),
+		Types(proj.types...),
+	}
+	if cursor >= 0 {
+		opts = append(opts, since(cursor+1)) // Since grabs an inclusive list, so grab 1 after the latest version
 	}
 
-	return msgs, nil
+	return proj.ms.Iterate(ctx, opts...)
 }
@@ -2,8 +2,10 @@ package gomessagestore_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"sync"
 	"testing"
 
 	. "github.com/blackhatbrigade/gomessagestore"
@@ -20,6 +22,13 @@ import (
   4. TestCreateProjectorFailsIfGivenPointerForDefaultState
   5. TestCreateProjectorFailsIfDefaultStateIsNotSet
   6. TestCreateProjectorFailsWithoutAtLeastOneReducer
+  7. TestProjectorRunWithVersionReturnsStreamVersion
+  8. TestProjectorRunWithVersionOnEmptyStream
+  9. TestProjectorRunOnStreamFromAppliesOnlyNewerMessages
+  10. TestProjectorRunOnCategoryFromFoldsOverCategory
+  11. TestProjectorStateFactoryGivesEachRunItsOwnState
+  12. TestCreateProjectorStateFactoryValidation
+  13. TestProjectorStrictMode
 */
 
 func TestProjectorAcceptsAReducer(t *testing.T) {
@@ -153,7 +162,7 @@ func TestProjectorRunsWithReducers(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		GetAllMessagesInStream(ctx, mockEventEnvs[0].StreamName, 1000).
+		GetAllMessagesInStreamSince(ctx, mockEventEnvs[0].StreamName, int64(0), 1000, "Event MessageType 1", "Event MessageType 2").
 		Return(mockEventEnvs, nil)
 
 	projection, err := myprojector.Run(ctx, expectedEvents[0].StreamCategory, expectedEvents[0].EntityID)
@@ -229,13 +238,26 @@ func TestProjectorRunsWithReducerErrors(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		GetAllMessagesInStream(ctx, mockEventEnvs[0].StreamName, 1000).
+		GetAllMessagesInStreamSince(ctx, mockEventEnvs[0].StreamName, int64(0), 1000, "Event MessageType 1", "Event MessageType 2").
 		Return(mockEventEnvs, nil)
 
 	_, err = myprojector.Run(ctx, expectedEvents[0].StreamCategory, expectedEvents[0].EntityID)
 
-	if err != mockError {
-		t.Errorf("An incorrect error has occurred with running a projector, err: %s", err)
+	projErr, ok := err.(*ProjectionError)
+	if !ok {
+		t.Fatalf("Expected a *ProjectionError, got %T: %v", err, err)
+	}
+
+	if projErr.Err != mockError {
+		t.Errorf("An incorrect error has occurred with running a projector, err: %s", projErr.Err)
+	}
+
+	if projErr.StreamName != mockEventEnvs[0].StreamName || projErr.Version != mockEventEnvs[0].Version {
+		t.Errorf("Error points at the wrong message: %s", projErr)
+	}
+
+	if !errors.Is(err, mockError) {
+		t.Errorf("Expected errors.Is to find the reducer's error through the ProjectionError, err: %s", err)
 	}
 }
 
@@ -273,12 +295,12 @@ func TestProjectorPicksUpAfterFullBatch(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		GetAllMessagesInStream(ctx, mockEventEnvsBatch1[0].StreamName, 1000).
+		GetAllMessagesInStreamSince(ctx, mockEventEnvsBatch1[0].StreamName, int64(0), 1000, "Event MessageType 1", "Event MessageType 2").
 		Return(mockEventEnvsBatch1, nil)
 
 	mockRepo.
 		EXPECT().
-		GetAllMessagesInStreamSince(ctx, mockEventEnvsBatch1[0].StreamName, mockEventEnvsBatch1[len(mockEventEnvsBatch1)-1].Version+1, 1000).
+		GetAllMessagesInStreamSince(ctx, mockEventEnvsBatch1[0].StreamName, mockEventEnvsBatch1[len(mockEventEnvsBatch1)-1].Version+1, 1000, "Event MessageType 1", "Event MessageType 2").
 		Return(mockEventEnvsBatch2, nil)
 
 	projection, err := myprojector.Run(ctx, expectedEvents[0].StreamCategory, expectedEvents[0].EntityID)
@@ -418,7 +440,7 @@ func TestProjectorRunsWithReducersForStream(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		GetAllMessagesInStream(ctx, mockEventEnvs[0].StreamName, 1000).
+		GetAllMessagesInStreamSince(ctx, mockEventEnvs[0].StreamName, int64(0), 1000, "Event MessageType 1", "Event MessageType 2").
 		Return(mockEventEnvs, nil)
 
 	projection, err := myprojector.RunOnStream(ctx, expectedEvents[0].StreamCategory+"-"+expectedEvents[0].EntityID.String())
@@ -484,12 +506,12 @@ func TestProjectorPicksUpAfterFullBatchForStream(t *testing.T) {
 
 	mockRepo.
 		EXPECT().
-		GetAllMessagesInStream(ctx, mockEventEnvsBatch1[0].StreamName, 1000).
+		GetAllMessagesInStreamSince(ctx, mockEventEnvsBatch1[0].StreamName, int64(0), 1000, "Event MessageType 1", "Event MessageType 2").
 		Return(mockEventEnvsBatch1, nil)
 
 	mockRepo.
 		EXPECT().
-		GetAllMessagesInStreamSince(ctx, mockEventEnvsBatch1[0].StreamName, mockEventEnvsBatch1[len(mockEventEnvsBatch1)-1].Version+1, 1000).
+		GetAllMessagesInStreamSince(ctx, mockEventEnvsBatch1[0].StreamName, mockEventEnvsBatch1[len(mockEventEnvsBatch1)-1].Version+1, 1000, "Event MessageType 1", "Event MessageType 2").
 		Return(mockEventEnvsBatch2, nil)
 
 	projection, err := myprojector.RunOnStream(ctx, expectedEvents[0].StreamCategory+"-"+expectedEvents[0].EntityID.String())
@@ -520,3 +542,372 @@ func TestProjectorPicksUpAfterFullBatchForStream(t *testing.T) {
 		}
 	}
 }
+
+func TestProjectorRunWithVersionReturnsStreamVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	var logrusLogger = logrus.New()
+	logrusLogger.Out = ioutil.Discard
+	myMessageStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+
+	myprojector, err := myMessageStore.CreateProjector(
+		DefaultState(mockDataStructure{}),
+		WithReducer(new(mockReducer2)), // only handles the first message, the version should still come from the last one
+	)
+	panicIf(err)
+
+	mockEventEnvs := getSampleEventsAsEnvelopes()
+	expectedEvents := getSampleEvents()
+	ctx := context.Background()
+
+	gomock.InOrder(
+		mockRepo.
+			EXPECT().
+			GetStreamVersion(ctx, mockEventEnvs[0].StreamName).
+			Return(mockEventEnvs[1].Version, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInStreamSince(ctx, mockEventEnvs[0].StreamName, int64(0), 1000, "Event MessageType 2").
+			Return(mockEventEnvs[:1], nil), // only the type with a reducer is read
+	)
+
+	projection, err := myprojector.RunWithVersion(ctx, expectedEvents[0].StreamCategory, expectedEvents[0].EntityID)
+	if err != nil {
+		t.Fatalf("An error has occurred with running a projector, err: %s", err)
+	}
+
+	if !projection.Exists {
+		t.Error("Projection should report that the stream exists")
+	}
+
+	if projection.Version != expectedEvents[1].MessageVersion {
+		t.Errorf("Wrong version for projection\nExpected: %d\n     Got: %d\n", expectedEvents[1].MessageVersion, projection.Version)
+	}
+
+	myStruct, ok := projection.State.(mockDataStructure)
+	if !ok {
+		t.Fatalf("Received incorrect type of state back: %T", projection.State)
+	}
+	if myStruct.MockReducer2CallCount != 1 {
+		t.Errorf("Reducer 2 was not called the correct number of times:\nExpected: 1\n     Got: %d\n", myStruct.MockReducer2CallCount)
+	}
+}
+
+func TestProjectorRunWithVersionOnEmptyStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	var logrusLogger = logrus.New()
+	logrusLogger.Out = ioutil.Discard
+	myMessageStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+
+	defstate := mockDataStructure{}
+	myprojector, err := myMessageStore.CreateProjector(
+		DefaultState(defstate),
+		WithReducer(new(mockReducer1)),
+	)
+	panicIf(err)
+
+	ctx := context.Background()
+
+	mockRepo.
+		EXPECT().
+		GetStreamVersion(ctx, "nothing-here").
+		Return(int64(-1), nil)
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamSince(ctx, "nothing-here", int64(0), 1000, "Event MessageType 1").
+		Return(nil, nil)
+
+	projection, err := myprojector.RunOnStreamWithVersion(ctx, "nothing-here")
+	if err != nil {
+		t.Fatalf("An error has occurred with running a projector, err: %s", err)
+	}
+
+	if projection.Exists {
+		t.Error("Projection should report that the stream does not exist")
+	}
+
+	if projection.Version != -1 {
+		t.Errorf("Wrong version for projection\nExpected: -1\n     Got: %d\n", projection.Version)
+	}
+
+	if projection.State != defstate {
+		t.Errorf("Expected the default state, got %+v", projection.State)
+	}
+}
+
+func TestProjectorRunOnStreamFromAppliesOnlyNewerMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	var logrusLogger = logrus.New()
+	logrusLogger.Out = ioutil.Discard
+	myMessageStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+
+	myprojector, err := myMessageStore.CreateProjector(
+		DefaultState(mockDataStructure{}),
+		WithReducer(new(mockReducer1)),
+		WithReducer(new(mockReducer2)),
+	)
+	panicIf(err)
+
+	mockEventEnvs := getSampleEventsAsEnvelopes()
+	ctx := context.Background()
+	previousState := mockDataStructure{MockReducer2Called: true, MockReducer2CallCount: 1}
+
+	mockRepo.
+		EXPECT().
+		GetStreamVersion(ctx, mockEventEnvs[0].StreamName).
+		Return(mockEventEnvs[1].Version, nil)
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamSince(ctx, mockEventEnvs[0].StreamName, mockEventEnvs[0].Version+1, 1000, "Event MessageType 1", "Event MessageType 2").
+		Return(mockEventEnvs[1:], nil)
+
+	projection, err := myprojector.RunOnStreamFrom(ctx, mockEventEnvs[0].StreamName, previousState, mockEventEnvs[0].Version)
+	if err != nil {
+		t.Fatalf("An error has occurred with running a projector, err: %s", err)
+	}
+
+	if projection.Version != mockEventEnvs[1].Version {
+		t.Errorf("Wrong version for projection\nExpected: %d\n     Got: %d\n", mockEventEnvs[1].Version, projection.Version)
+	}
+
+	expectedState := mockDataStructure{
+		MockReducer1Called:    true,
+		MockReducer1CallCount: 1,
+		MockReducer2Called:    true,
+		MockReducer2CallCount: 1,
+	}
+	if projection.State != expectedState {
+		t.Errorf("Wrong state for projection\nExpected: %+v\n     Got: %+v\n", expectedState, projection.State)
+	}
+}
+
+func TestProjectorRunOnCategoryFromFoldsOverCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	var logrusLogger = logrus.New()
+	logrusLogger.Out = ioutil.Discard
+	myMessageStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+
+	myprojector, err := myMessageStore.CreateProjector(
+		DefaultState(mockDataStructure{}),
+		WithReducer(new(mockReducer1)),
+		WithReducer(new(mockReducer2)),
+	)
+	panicIf(err)
+
+	mockEventEnvsBatch1 := getLotsOfSampleEventsAsEnvelopes(1000, 0)
+	mockEventEnvsBatch2 := getLotsOfSampleEventsAsEnvelopes(500, 1000)
+	ctx := context.Background()
+
+	gomock.InOrder(
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInCategorySince(ctx, "test cat", int64(100), 1000, "Event MessageType 1", "Event MessageType 2").
+			Return(mockEventEnvsBatch1, nil),
+		mockRepo.
+			EXPECT().
+			GetAllMessagesInCategorySince(ctx, "test cat", mockEventEnvsBatch1[len(mockEventEnvsBatch1)-1].GlobalPosition+1, 1000, "Event MessageType 1", "Event MessageType 2").
+			Return(mockEventEnvsBatch2, nil),
+	)
+
+	projection, err := myprojector.RunOnCategoryFrom(ctx, "test cat", mockDataStructure{}, 99)
+	if err != nil {
+		t.Fatalf("An error has occurred with running a projector, err: %s", err)
+	}
+
+	lastPosition := mockEventEnvsBatch2[len(mockEventEnvsBatch2)-1].GlobalPosition
+	if projection.Version != lastPosition {
+		t.Errorf("Wrong position for projection\nExpected: %d\n     Got: %d\n", lastPosition, projection.Version)
+	}
+
+	myStruct := projection.State.(mockDataStructure)
+	if myStruct.MockReducer1CallCount+myStruct.MockReducer2CallCount != 1500 {
+		t.Errorf("Reducers were not called for every message in the category: %+v", myStruct)
+	}
+}
+
+func TestProjectorStateFactoryGivesEachRunItsOwnState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	var logrusLogger = logrus.New()
+	logrusLogger.Out = ioutil.Discard
+	myMessageStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+
+	// counts messages by type in place, which is only safe because every run gets its own map
+	countingReducer := func(msg Message, previousState interface{}) (interface{}, error) {
+		counts := previousState.(*map[string]int)
+		(*counts)[msg.Type()]++
+		return counts, nil
+	}
+
+	myprojector, err := myMessageStore.CreateProjector(
+		StateFactory(func() interface{} {
+			counts := make(map[string]int)
+			return &counts
+		}),
+		WithReducerFunc("Event MessageType 1", countingReducer),
+		WithReducerFunc("Event MessageType 2", countingReducer),
+	)
+	if err != nil {
+		t.Fatalf("Error creating projector: %s", err)
+	}
+
+	mockEventEnvs := getSampleEventsAsEnvelopes()
+	ctx := context.Background()
+
+	mockRepo.
+		EXPECT().
+		GetAllMessagesInStreamSince(ctx, mockEventEnvs[0].StreamName, int64(0), 1000, "Event MessageType 1", "Event MessageType 2").
+		Return(mockEventEnvs, nil).
+		AnyTimes()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			projection, err := myprojector.RunOnStream(ctx, mockEventEnvs[0].StreamName)
+			if err != nil {
+				t.Errorf("An error has occurred with running a projector, err: %s", err)
+				return
+			}
+
+			counts := *projection.(*map[string]int)
+			if counts["Event MessageType 1"] != 1 || counts["Event MessageType 2"] != 1 {
+				t.Errorf("Runs shared state: %v", counts)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCreateProjectorStateFactoryValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	var logrusLogger = logrus.New()
+	logrusLogger.Out = ioutil.Discard
+	myMessageStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+
+	_, err := myMessageStore.CreateProjector(
+		DefaultState(mockDataStructure{}),
+		StateFactory(func() interface{} { return new(mockDataStructure) }),
+		WithReducer(new(mockReducer1)),
+	)
+	if err != ErrDefaultStateAndStateFactory {
+		t.Errorf("Expected ErrDefaultStateAndStateFactory and got %v\n", err)
+	}
+
+	myprojector, err := myMessageStore.CreateProjector(
+		StateFactory(func() interface{} { return nil }),
+		WithReducer(new(mockReducer1)),
+	)
+	if err != nil {
+		t.Fatalf("Error creating projector: %s", err)
+	}
+
+	_, err = myprojector.RunOnStream(context.Background(), "some-stream")
+	if err != ErrStateFactoryReturnedNil {
+		t.Errorf("Expected ErrStateFactoryReturnedNil and got %v\n", err)
+	}
+}
+
+func TestProjectorStrictMode(t *testing.T) {
+	mockEventEnvs := getSampleEventsAsEnvelopes() // a "Event MessageType 2" followed by a "Event MessageType 1"
+
+	tests := []struct {
+		name              string
+		opts              []ProjectorOption
+		expectedErr       error
+		expectedUnhandled []string
+	}{{
+		name:              "unknown types are skipped by default, but passed to OnUnhandled",
+		expectedUnhandled: []string{"Event MessageType 2"},
+	}, {
+		name:              "unknown types fail the projection in strict mode",
+		opts:              []ProjectorOption{StrictMode()},
+		expectedErr:       ErrUnhandledMessageType,
+		expectedUnhandled: []string{"Event MessageType 2"},
+	}, {
+		name: "ignored types pass through strict mode",
+		opts: []ProjectorOption{StrictMode(), IgnoreTypes("Event MessageType 2")},
+	}, {
+		name: "ignored types are not passed to OnUnhandled",
+		opts: []ProjectorOption{IgnoreTypes("Event MessageType 2")},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+
+			var logrusLogger = logrus.New()
+			logrusLogger.Out = ioutil.Discard
+			myMessageStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+
+			var unhandled []string
+			opts := append([]ProjectorOption{
+				DefaultState(mockDataStructure{}),
+				WithReducer(new(mockReducer1)),
+				OnUnhandled(func(msg Message) {
+					unhandled = append(unhandled, msg.Type())
+				}),
+			}, test.opts...)
+
+			myprojector, err := myMessageStore.CreateProjector(opts...)
+			panicIf(err)
+
+			ctx := context.Background()
+
+			mockRepo.
+				EXPECT().
+				GetAllMessagesInStream(ctx, mockEventEnvs[0].StreamName, 1000).
+				Return(mockEventEnvs, nil)
+
+			_, err = myprojector.RunOnStream(ctx, mockEventEnvs[0].StreamName)
+
+			if test.expectedErr == nil {
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err)
+				}
+			} else {
+				projErr, ok := err.(*ProjectionError)
+				if !ok {
+					t.Fatalf("Expected a *ProjectionError, got %T: %v", err, err)
+				}
+				if projErr.Err != test.expectedErr {
+					t.Errorf("Expected %v and got %v", test.expectedErr, projErr.Err)
+				}
+				if projErr.MessageType != mockEventEnvs[0].MessageType || projErr.GlobalPosition != mockEventEnvs[0].GlobalPosition {
+					t.Errorf("Error points at the wrong message: %s", projErr)
+				}
+			}
+
+			if fmt.Sprint(unhandled) != fmt.Sprint(test.expectedUnhandled) {
+				t.Errorf("Wrong unhandled messages\nExpected: %v\n     Got: %v\n", test.expectedUnhandled, unhandled)
+			}
+		})
+	}
+}
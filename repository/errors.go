@@ -11,6 +11,8 @@ const (
 	ErrInvalidSubscriberPosition = Error("Subscriber position must be greater than or equal to -1")
 	ErrNilMessage                = Error("Message cannot be nil")
 	ErrInvalidPosition           = Error("position must be greater than equal to -1")
+	ErrExpectedVersionFailed     = Error("Wrong expected version")
+	ErrDuplicateMessageID        = Error("Message with that ID has already been written")
 )
 
 // allows the creation of constant errors
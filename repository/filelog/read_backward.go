@@ -0,0 +1,67 @@
+package filelog
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+)
+
+func (fl *fileLog) GetAllMessagesInStreamBackward(ctx context.Context, streamName string, version int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+	if streamName == "" {
+		return nil, repository.ErrInvalidStreamName
+	}
+	if batchSize < 0 {
+		return nil, repository.ErrNegativeBatchSize
+	}
+
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+
+	locations := fl.streams[streamName]
+	if version < 0 {
+		version = -1
+	}
+	if version >= int64(len(locations)) {
+		version = int64(len(locations)) - 1
+	}
+
+	return fl.read(backward(locations[:version+1], batchSize), batchSize)
+}
+
+func (fl *fileLog) GetAllMessagesInCategoryBackward(ctx context.Context, category string, globalPosition int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+	if category == "" {
+		return nil, repository.ErrBlankCategory
+	}
+	if batchSize < 0 {
+		return nil, repository.ErrNegativeBatchSize
+	}
+	if strings.Contains(category, "-") {
+		return nil, repository.ErrInvalidCategory
+	}
+
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+
+	locations := fl.categories[category]
+	end := sort.Search(len(locations), func(i int) bool {
+		return locations[i].globalPosition > globalPosition
+	})
+
+	return fl.read(backward(locations[:end], batchSize), batchSize)
+}
+
+// backward copies the last batchSize locations, newest first, where a batchSize of 0 means all of them
+func backward(locations []location, batchSize int) []location {
+	if batchSize > 0 && len(locations) > batchSize {
+		locations = locations[len(locations)-batchSize:]
+	}
+
+	reversed := make([]location, len(locations))
+	for i, loc := range locations {
+		reversed[len(locations)-1-i] = loc
+	}
+
+	return reversed
+}
@@ -0,0 +1,62 @@
+package filelog
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+)
+
+// Messages are written one at a time and stamped as they're written, so their times only ever go up and can be binary searched
+
+func (fl *fileLog) GetStreamVersionAtTime(ctx context.Context, streamName string, t time.Time) (int64, error) {
+	if streamName == "" {
+		return 0, repository.ErrInvalidStreamName
+	}
+
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+
+	version, err := fl.searchTime(fl.streams[streamName], t)
+	return int64(version), err
+}
+
+func (fl *fileLog) GetGlobalPositionAtTime(ctx context.Context, t time.Time) (int64, error) {
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+
+	i, err := fl.searchTime(fl.all, t)
+	if err != nil {
+		return 0, err
+	}
+	if i == len(fl.all) {
+		return fl.globalPosition + 1, nil
+	}
+
+	return fl.all[i].globalPosition, nil
+}
+
+// searchTime finds the index of the first message written at or after a time, or len(locations) when there is none; the lock must be held
+func (fl *fileLog) searchTime(locations []location, t time.Time) (int, error) {
+	if fl.closed {
+		return 0, ErrLogClosed
+	}
+
+	var err error
+	i := sort.Search(len(locations), func(i int) bool {
+		if err != nil {
+			return true
+		}
+
+		var msg *repository.MessageEnvelope
+		if msg, err = fl.segments[locations[i].segment].read(locations[i]); err != nil {
+			fl.log.WithError(err).Error("Failure in filelog/read_time.go::searchTime")
+			return true
+		}
+
+		return !msg.Time.Before(t)
+	})
+
+	return i, err
+}
@@ -0,0 +1,168 @@
+package filelog
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+)
+
+func (fl *fileLog) GetAllMessagesInStream(ctx context.Context, streamName string, batchSize int) ([]*repository.MessageEnvelope, error) {
+	return fl.GetAllMessagesInStreamSince(ctx, streamName, 0, batchSize)
+}
+
+func (fl *fileLog) GetAllMessagesInStreamSince(ctx context.Context, streamName string, version int64, batchSize int, types ...string) ([]*repository.MessageEnvelope, error) {
+	if streamName == "" {
+		return nil, repository.ErrInvalidStreamName
+	}
+	if batchSize < 0 {
+		return nil, repository.ErrNegativeBatchSize
+	}
+
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+
+	locations := fl.streams[streamName]
+	if version < 0 {
+		version = 0
+	}
+	if version > int64(len(locations)) {
+		version = int64(len(locations))
+	}
+
+	return fl.read(ofTypes(locations[version:], types, batchSize), batchSize)
+}
+
+func (fl *fileLog) GetLastMessageInStream(ctx context.Context, streamName string) (*repository.MessageEnvelope, error) {
+	if streamName == "" {
+		return nil, repository.ErrInvalidStreamName
+	}
+
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+
+	locations := fl.streams[streamName]
+	if len(locations) == 0 {
+		return nil, nil
+	}
+
+	msgs, err := fl.read(locations[len(locations)-1:], 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return msgs[0], nil
+}
+
+func (fl *fileLog) GetStreamVersion(ctx context.Context, streamName string) (int64, error) {
+	if streamName == "" {
+		return 0, repository.ErrInvalidStreamName
+	}
+
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+
+	if fl.closed {
+		return 0, ErrLogClosed
+	}
+
+	return int64(len(fl.streams[streamName]) - 1), nil
+}
+
+func (fl *fileLog) GetAllMessagesInCategory(ctx context.Context, category string, batchSize int) ([]*repository.MessageEnvelope, error) {
+	return fl.GetAllMessagesInCategorySince(ctx, category, 0, batchSize)
+}
+
+func (fl *fileLog) GetAllMessagesInCategorySince(ctx context.Context, category string, globalPosition int64, batchSize int, types ...string) ([]*repository.MessageEnvelope, error) {
+	if category == "" {
+		return nil, repository.ErrBlankCategory
+	}
+	if batchSize < 0 {
+		return nil, repository.ErrNegativeBatchSize
+	}
+	if strings.Contains(category, "-") {
+		return nil, repository.ErrInvalidCategory
+	}
+
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+
+	locations := fl.categories[category]
+	first := sort.Search(len(locations), func(i int) bool {
+		return locations[i].globalPosition >= globalPosition
+	})
+
+	return fl.read(ofTypes(locations[first:], types, batchSize), batchSize)
+}
+
+func (fl *fileLog) GetAllMessagesSince(ctx context.Context, globalPosition int64, batchSize int, filter repository.CategoryFilter, types ...string) ([]*repository.MessageEnvelope, error) {
+	if batchSize < 0 {
+		return nil, repository.ErrNegativeBatchSize
+	}
+
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+
+	first := sort.Search(len(fl.all), func(i int) bool {
+		return fl.all[i].globalPosition >= globalPosition
+	})
+
+	var locations []location
+	for _, loc := range fl.all[first:] {
+		if batchSize > 0 && len(locations) == batchSize {
+			break
+		}
+
+		if filter.Matches(loc.category) && repository.MatchesType(loc.messageType, types) {
+			locations = append(locations, loc)
+		}
+	}
+
+	return fl.read(locations, batchSize)
+}
+
+// ofTypes returns up to batchSize of the locations that hold messages of the types, where a batchSize of 0 means no limit;
+// no types reads every type
+func ofTypes(locations []location, types []string, batchSize int) []location {
+	if len(types) == 0 {
+		return locations
+	}
+
+	var matching []location
+	for _, loc := range locations {
+		if batchSize > 0 && len(matching) == batchSize {
+			break
+		}
+
+		if repository.MatchesType(loc.messageType, types) {
+			matching = append(matching, loc)
+		}
+	}
+
+	return matching
+}
+
+// read reads up to batchSize messages from the segments, where a batchSize of 0 means no limit; the lock must be held
+func (fl *fileLog) read(locations []location, batchSize int) ([]*repository.MessageEnvelope, error) {
+	if fl.closed {
+		return nil, ErrLogClosed
+	}
+
+	if batchSize > 0 && len(locations) > batchSize {
+		locations = locations[:batchSize]
+	}
+
+	msgs := make([]*repository.MessageEnvelope, 0, len(locations))
+	for _, loc := range locations {
+		msg, err := fl.segments[loc.segment].read(loc)
+		if err != nil {
+			fl.log.WithError(err).Error("Failure in filelog/reads.go::read")
+			return nil, err
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
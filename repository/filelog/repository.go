@@ -0,0 +1,261 @@
+// Package filelog is a Repository that keeps messages in an append-only log of segment files in a directory on local disk.
+//
+// Every message is appended to the newest segment as a length prefixed, checksummed record, and a new segment is started
+// once the newest one reaches the segment size. The index from streams and categories to records is rebuilt in memory when
+// the log is opened, and a torn write at the end of the newest segment (from a crash part way through a write) is truncated
+// away. Versions, global positions, expected versions and categories behave just like the Eventide message store.
+package filelog
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+//Errors returned only by the file log
+const (
+	ErrCorruptLog          = repository.Error("Log has a corrupt record before the end of its newest segment")
+	ErrLogClosed           = repository.Error("Log has been closed")
+	ErrInvalidSegmentSize  = repository.Error("Segment size must be greater than zero")
+	ErrInvalidSyncInterval = repository.Error("Sync interval must be greater than zero")
+)
+
+// defaultSegmentSize is how big a segment gets before the next message starts a new one
+const defaultSegmentSize = 64 * 1024 * 1024
+
+//Repository is a file log repository, which needs to be closed when it's no longer used
+type Repository interface {
+	repository.Repository
+	io.Closer
+}
+
+//Option configures a file log when it's opened
+type Option func(log *fileLog) error
+
+//SegmentSize sets how many bytes a segment can hold before a new one is started (default 64MB)
+func SegmentSize(bytes int64) Option {
+	return func(log *fileLog) error {
+		if bytes <= 0 {
+			return ErrInvalidSegmentSize
+		}
+
+		log.segmentSize = bytes
+		return nil
+	}
+}
+
+//SyncEveryWrite fsyncs after every message written, so a write that returned is never lost (the default)
+func SyncEveryWrite() Option {
+	return func(log *fileLog) error {
+		log.syncInterval = 0
+		log.syncNever = false
+		return nil
+	}
+}
+
+//SyncEvery fsyncs in the background on an interval, trading the last interval's writes in a power loss for write speed
+func SyncEvery(interval time.Duration) Option {
+	return func(log *fileLog) error {
+		if interval <= 0 {
+			return ErrInvalidSyncInterval
+		}
+
+		log.syncInterval = interval
+		log.syncNever = false
+		return nil
+	}
+}
+
+//SyncNever leaves flushing to the operating system, only fsyncing when the log is closed; best for tests
+func SyncNever() Option {
+	return func(log *fileLog) error {
+		log.syncInterval = 0
+		log.syncNever = true
+		return nil
+	}
+}
+
+// location is where a message's record lives
+type location struct {
+	globalPosition int64
+	category       string
+	messageType    string
+	segment        int
+	offset         int64
+	size           int64
+}
+
+type fileLog struct {
+	dir          string
+	log          logrus.FieldLogger
+	segmentSize  int64
+	syncInterval time.Duration
+	syncNever    bool
+
+	mutex          sync.RWMutex
+	closed         bool
+	segments       []*segment
+	streams        map[string][]location // indexed by version
+	categories     map[string][]location // in global position order
+	all            []location            // every message, in global position order
+	ids            map[uuid.UUID]bool
+	globalPosition int64 // of the last message written
+	stopSyncing    chan struct{}
+	syncingStopped chan struct{}
+}
+
+//NewFileLogRepository opens the log in dir, creating the directory if needed, and recovers from any torn write at its end
+func NewFileLogRepository(dir string, log logrus.FieldLogger, opts ...Option) (Repository, error) {
+	fl := &fileLog{
+		dir:         dir,
+		log:         log,
+		segmentSize: defaultSegmentSize,
+		streams:     make(map[string][]location),
+		categories:  make(map[string][]location),
+		ids:         make(map[uuid.UUID]bool),
+	}
+
+	for _, option := range opts {
+		if err := option(fl); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	if err := fl.recover(); err != nil {
+		fl.closeSegments()
+		return nil, err
+	}
+
+	if fl.syncInterval > 0 {
+		fl.stopSyncing = make(chan struct{})
+		fl.syncingStopped = make(chan struct{})
+		go fl.syncEvery(fl.syncInterval)
+	}
+
+	return fl, nil
+}
+
+// recover opens every segment in order and rebuilds the indexes from their records
+func (fl *fileLog) recover() error {
+	names, err := filepath.Glob(filepath.Join(fl.dir, "*"+segmentExtension))
+	if err != nil {
+		return err
+	}
+	sort.Strings(names) // segment names are zero padded, so they sort in order
+
+	for i, name := range names {
+		seg, err := openSegment(name)
+		if err != nil {
+			return err
+		}
+		fl.segments = append(fl.segments, seg)
+
+		last := i == len(names)-1
+		err = seg.scan(last, func(env *repository.MessageEnvelope, offset, size int64) error {
+			if env.GlobalPosition != fl.globalPosition+1 {
+				return ErrCorruptLog
+			}
+
+			fl.index(env, location{globalPosition: env.GlobalPosition, segment: i, offset: offset, size: size})
+			return nil
+		})
+		if err == errTornRecord {
+			fl.log.WithField("segment", name).Warnf("truncating torn write at offset %d", seg.size)
+			if err := seg.truncate(); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// index adds a message to the in memory indexes; the write lock must be held
+func (fl *fileLog) index(env *repository.MessageEnvelope, loc location) {
+	loc.category = repository.Category(env.StreamName)
+	loc.messageType = env.MessageType
+
+	fl.streams[env.StreamName] = append(fl.streams[env.StreamName], loc)
+	fl.categories[loc.category] = append(fl.categories[loc.category], loc)
+	fl.all = append(fl.all, loc)
+	fl.ids[env.ID] = true
+	fl.globalPosition = env.GlobalPosition
+}
+
+// syncEvery fsyncs the newest segment on an interval until the log is closed
+func (fl *fileLog) syncEvery(interval time.Duration) {
+	defer close(fl.syncingStopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			fl.mutex.RLock()
+			if len(fl.segments) > 0 {
+				if err := fl.segments[len(fl.segments)-1].sync(); err != nil {
+					fl.log.WithError(err).Error("Failure in filelog/repository.go::syncEvery")
+				}
+			}
+			fl.mutex.RUnlock()
+		case <-fl.stopSyncing:
+			return
+		}
+	}
+}
+
+//Close syncs and closes every segment; the log can't be used afterwards
+func (fl *fileLog) Close() error {
+	fl.mutex.Lock()
+	if fl.closed {
+		fl.mutex.Unlock()
+		return nil
+	}
+	fl.closed = true
+	fl.mutex.Unlock()
+
+	if fl.stopSyncing != nil {
+		close(fl.stopSyncing)
+		<-fl.syncingStopped
+	}
+
+	fl.mutex.Lock()
+	defer fl.mutex.Unlock()
+
+	var err error
+	if len(fl.segments) > 0 {
+		err = fl.segments[len(fl.segments)-1].sync()
+	}
+
+	if closeErr := fl.closeSegments(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// closeSegments closes every segment file, returning the first error
+func (fl *fileLog) closeSegments() error {
+	var err error
+	for _, seg := range fl.segments {
+		if closeErr := seg.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
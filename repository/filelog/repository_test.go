@@ -0,0 +1,323 @@
+package filelog_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	. "github.com/blackhatbrigade/gomessagestore/repository/filelog"
+	"github.com/blackhatbrigade/gomessagestore/repository/repositorytest"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// tempDir makes a directory for a log, and returns a function that removes it again
+func tempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "gomessagestore")
+	if err != nil {
+		t.Fatalf("failed to create a temporary directory: %s", err)
+	}
+
+	return dir, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+// open opens the log in dir, failing the test on any error
+func open(t *testing.T, dir string, opts ...Option) Repository {
+	repo, err := NewFileLogRepository(dir, logrus.New(), opts...)
+	if err != nil {
+		t.Fatalf("failed to open log: %s", err)
+	}
+
+	return repo
+}
+
+// writeMessages writes count messages to a stream, failing the test on any error
+func writeMessages(t *testing.T, repo Repository, stream string, count int) []uuid.UUID {
+	var ids []uuid.UUID
+	for i := 0; i < count; i++ {
+		msg := &repository.MessageEnvelope{
+			ID:          uuid.NewRandom(),
+			StreamName:  stream,
+			MessageType: "Happened",
+			Data:        []byte(`{"some":"data"}`),
+		}
+		if err := repo.WriteMessage(context.Background(), msg); err != nil {
+			t.Fatalf("failed to write message: %s", err)
+		}
+
+		ids = append(ids, msg.ID)
+	}
+
+	return ids
+}
+
+// readIDs reads the IDs of every message in a stream
+func readIDs(t *testing.T, repo Repository, stream string) []uuid.UUID {
+	msgs, err := repo.GetAllMessagesInStream(context.Background(), stream, 0)
+	if err != nil {
+		t.Fatalf("failed to read messages: %s", err)
+	}
+
+	var ids []uuid.UUID
+	for _, msg := range msgs {
+		ids = append(ids, msg.ID)
+	}
+
+	return ids
+}
+
+// segments lists the segment files in a log's directory, in order
+func segments(t *testing.T, dir string) []string {
+	names, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if err != nil {
+		t.Fatalf("failed to list segments: %s", err)
+	}
+
+	return names
+}
+
+func TestFileLogRepoConformance(t *testing.T) {
+	for _, opts := range [][]Option{{}, {SyncNever()}, {SyncEvery(time.Millisecond), SegmentSize(512)}} {
+		var cleanups []func()
+		repositorytest.RunConformance(t, func(t *testing.T) repository.Repository {
+			dir, cleanup := tempDir(t)
+			repo := open(t, dir, opts...)
+			cleanups = append(cleanups, func() {
+				repo.Close()
+				cleanup()
+			})
+
+			return repo
+		})
+
+		for _, cleanup := range cleanups {
+			cleanup()
+		}
+	}
+}
+
+func TestFileLogRepoRollsOverSegmentsAndReopens(t *testing.T) {
+	assert := assert.New(t)
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	repo := open(t, dir, SegmentSize(1024))
+	written := writeMessages(t, repo, "thing-1", 30)
+	assert.Nil(repo.Close())
+
+	assert.True(len(segments(t, dir)) > 1)
+
+	repo = open(t, dir, SegmentSize(1024))
+	defer repo.Close()
+	assert.Equal(written, readIDs(t, repo, "thing-1"))
+
+	written = append(written, writeMessages(t, repo, "thing-1", 1)...)
+	last, err := repo.GetLastMessageInStream(context.Background(), "thing-1")
+	assert.Nil(err)
+	assert.Equal(int64(30), last.Version)
+	assert.Equal(int64(31), last.GlobalPosition)
+	assert.Equal(written, readIDs(t, repo, "thing-1"))
+}
+
+func TestFileLogRepoTruncatesTornWrites(t *testing.T) {
+	tests := []struct {
+		name     string
+		tear     func(data []byte) []byte
+		lostLast bool
+	}{{
+		name: "when the last record is cut short it is truncated",
+		tear: func(data []byte) []byte {
+			return data[:len(data)-5]
+		},
+		lostLast: true,
+	}, {
+		name: "when the last record is only part of a header it is truncated",
+		tear: func(data []byte) []byte {
+			return append(data, 0, 0, 1)
+		},
+	}, {
+		name: "when the last record doesn't match its checksum it is truncated",
+		tear: func(data []byte) []byte {
+			data[len(data)-3] ^= 0xff
+			return data
+		},
+		lostLast: true,
+	}, {
+		name: "when the file was extended with zeroes it is truncated",
+		tear: func(data []byte) []byte {
+			return append(data, make([]byte, 100)...)
+		},
+	}, {
+		name: "when the last record has a length longer than the file it is truncated",
+		tear: func(data []byte) []byte {
+			return append(data, 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0, '{')
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			dir, cleanup := tempDir(t)
+			defer cleanup()
+
+			repo := open(t, dir)
+			written := writeMessages(t, repo, "thing-1", 3)
+			assert.Nil(repo.Close())
+
+			names := segments(t, dir)
+			data, err := ioutil.ReadFile(names[len(names)-1])
+			assert.Nil(err)
+			assert.Nil(ioutil.WriteFile(names[len(names)-1], test.tear(data), 0644))
+
+			repo = open(t, dir)
+			defer repo.Close()
+
+			found := readIDs(t, repo, "thing-1")
+			if test.lostLast {
+				written = written[:2]
+			}
+			assert.Equal(written, found)
+
+			// the next write carries on from the last good message
+			writeMessages(t, repo, "thing-1", 1)
+			last, err := repo.GetLastMessageInStream(context.Background(), "thing-1")
+			assert.Nil(err)
+			assert.Equal(int64(len(written)), last.Version)
+			assert.Equal(int64(len(written)+1), last.GlobalPosition)
+		})
+	}
+}
+
+func TestFileLogRepoRefusesCorruptionBeforeTheLastSegment(t *testing.T) {
+	assert := assert.New(t)
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	repo := open(t, dir, SegmentSize(512))
+	writeMessages(t, repo, "thing-1", 10)
+	assert.Nil(repo.Close())
+
+	names := segments(t, dir)
+	data, err := ioutil.ReadFile(names[0])
+	assert.Nil(err)
+	assert.Nil(ioutil.WriteFile(names[0], data[:len(data)-5], 0644))
+
+	_, err = NewFileLogRepository(dir, logrus.New(), SegmentSize(512))
+	assert.Equal(ErrCorruptLog, err)
+}
+
+func TestFileLogRepoRefusesCorruptionBeforeTheEndOfTheLastSegment(t *testing.T) {
+	assert := assert.New(t)
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	repo := open(t, dir)
+	writeMessages(t, repo, "thing-1", 3)
+	assert.Nil(repo.Close())
+
+	names := segments(t, dir)
+	data, err := ioutil.ReadFile(names[0])
+	assert.Nil(err)
+	data[8+5] ^= 0xff // inside the first record, past its 8 byte header, with two good records after it
+	assert.Nil(ioutil.WriteFile(names[0], data, 0644))
+
+	_, err = NewFileLogRepository(dir, logrus.New())
+	assert.Equal(ErrCorruptLog, err)
+
+	// nothing was truncated away
+	after, err := ioutil.ReadFile(names[0])
+	assert.Nil(err)
+	assert.Equal(len(data), len(after))
+}
+
+func TestFileLogRepoRefusesCorruptLengths(t *testing.T) {
+	tests := []struct {
+		name    string
+		segment func(names []string) string
+	}{{
+		name: "when a record in an older segment has a length past the end of the file",
+		segment: func(names []string) string {
+			return names[0]
+		},
+	}, {
+		name: "when a record in the middle of the newest segment has a length past the end of the file",
+		segment: func(names []string) string {
+			return names[len(names)-1]
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			dir, cleanup := tempDir(t)
+			defer cleanup()
+
+			repo := open(t, dir, SegmentSize(512))
+			writeMessages(t, repo, "thing-1", 10)
+			assert.Nil(repo.Close())
+
+			name := test.segment(segments(t, dir))
+			data, err := ioutil.ReadFile(name)
+			assert.Nil(err)
+			data[0], data[1] = 0xff, 0xff // the length of the first record, with good records after it
+			assert.Nil(ioutil.WriteFile(name, data, 0644))
+
+			_, err = NewFileLogRepository(dir, logrus.New(), SegmentSize(512))
+			assert.Equal(ErrCorruptLog, err)
+
+			// nothing was truncated away
+			after, err := ioutil.ReadFile(name)
+			assert.Nil(err)
+			assert.Equal(len(data), len(after))
+		})
+	}
+}
+
+func TestFileLogRepoOptions(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	tests := []struct {
+		name        string
+		option      Option
+		expectedErr error
+	}{{
+		name:        "when the segment size is zero an error is returned",
+		option:      SegmentSize(0),
+		expectedErr: ErrInvalidSegmentSize,
+	}, {
+		name:        "when the sync interval is negative an error is returned",
+		option:      SyncEvery(-time.Second),
+		expectedErr: ErrInvalidSyncInterval,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewFileLogRepository(dir, logrus.New(), test.option)
+			assert.Equal(t, test.expectedErr, err)
+		})
+	}
+}
+
+func TestFileLogRepoClosed(t *testing.T) {
+	assert := assert.New(t)
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+
+	repo := open(t, dir)
+	assert.Nil(repo.Close())
+	assert.Nil(repo.Close())
+
+	_, err := repo.GetAllMessagesInStream(context.Background(), "thing-1", 10)
+	assert.Equal(ErrLogClosed, err)
+
+	err = repo.WriteMessage(context.Background(), &repository.MessageEnvelope{ID: uuid.NewRandom(), StreamName: "thing-1"})
+	assert.Equal(ErrLogClosed, err)
+}
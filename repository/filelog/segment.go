@@ -0,0 +1,261 @@
+package filelog
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+)
+
+// segmentExtension ends the name of every segment file, the rest of the name being the global position of its first message
+const segmentExtension = ".log"
+
+// headerSize is the length and checksum in front of every record
+const headerSize = 8
+
+// errTornRecord is found when the newest segment ends with a record that's cut short or doesn't match its checksum, which is only expected after a crash
+var errTornRecord = errors.New("torn record")
+
+// syncFile fsyncs a segment file; tests swap it out to see what happens when that fails
+var syncFile = (*os.File).Sync
+
+// record is what's written to a segment for each message
+type record struct {
+	ID             uuid.UUID `json:"id"`
+	StreamName     string    `json:"streamName"`
+	MessageType    string    `json:"type"`
+	Version        int64     `json:"position"`
+	GlobalPosition int64     `json:"globalPosition"`
+	Data           []byte    `json:"data"`
+	Metadata       []byte    `json:"metadata"`
+	Time           time.Time `json:"time"`
+}
+
+type segment struct {
+	file *os.File
+	size int64 // everything after this is a torn write, or not written yet
+}
+
+// segmentName is the file name for a segment that starts with the message at the global position
+func segmentName(dir string, globalPosition int64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", globalPosition, segmentExtension))
+}
+
+// openSegment opens (or creates) a segment file; its size is found by scanning it
+func openSegment(name string) (*segment, error) {
+	file, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &segment{file: file}, nil
+}
+
+// scan calls found with every record in the segment, in order. Only the newest segment can end with a torn write, so for it
+// (last) scan returns errTornRecord if it ends with a bad record; anything else bad, in any segment, is ErrCorruptLog.
+func (seg *segment) scan(last bool, found func(env *repository.MessageEnvelope, offset, size int64) error) error {
+	info, err := seg.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(seg.file, 0, info.Size()))
+	seg.size = 0
+
+	torn := func(err error) error {
+		if err == errTornRecord && !last {
+			return ErrCorruptLog
+		}
+
+		return err
+	}
+
+	for {
+		header := make([]byte, headerSize)
+		if _, err := io.ReadFull(reader, header); err == io.EOF {
+			return nil
+		} else if err == io.ErrUnexpectedEOF {
+			return torn(errTornRecord)
+		} else if err != nil {
+			return err
+		}
+
+		length := int64(binary.BigEndian.Uint32(header[0:4]))
+		if seg.size+headerSize+length > info.Size() { // don't trust a torn length enough to allocate it
+			return torn(seg.pastEnd(info.Size()))
+		}
+
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(reader, payload); err == io.ErrUnexpectedEOF || err == io.EOF {
+			return torn(errTornRecord)
+		} else if err != nil {
+			return err
+		}
+
+		env, err := decode(header, payload)
+		if err != nil {
+			return torn(seg.badRecord(seg.size+headerSize+length, info.Size()))
+		}
+
+		size := int64(headerSize + len(payload))
+		if err := found(env, seg.size, size); err != nil {
+			return err
+		}
+		seg.size += size
+	}
+}
+
+// badRecord decides whether a record that doesn't match its checksum (starting at the segment's size and ending at end)
+// is a torn write: it is when it runs to the end of the file, or when everything from it on is zeroes, which is what's left
+// when a crash extends a file without writing to it. Anything else is corruption with good records after it, which
+// truncating would lose.
+func (seg *segment) badRecord(end, fileSize int64) error {
+	if end == fileSize {
+		return errTornRecord
+	}
+
+	zeroes, err := seg.zeroedFrom(seg.size, fileSize)
+	if err != nil {
+		return err
+	}
+	if zeroes {
+		return errTornRecord
+	}
+
+	return ErrCorruptLog
+}
+
+// pastEnd decides whether a record whose length runs past the end of the file (starting at the segment's size) is a torn
+// write, which is the last thing in the file, or a corrupt length with good records after it
+func (seg *segment) pastEnd(fileSize int64) error {
+	rest := make([]byte, fileSize-seg.size)
+	if _, err := seg.file.ReadAt(rest, seg.size); err != nil {
+		return err
+	}
+
+	for offset := 1; offset+headerSize < len(rest); offset++ {
+		length := int(binary.BigEndian.Uint32(rest[offset : offset+4]))
+		payload := rest[offset+headerSize:]
+		if length == 0 || length > len(payload) || payload[0] != '{' {
+			continue // every record is a JSON object
+		}
+		if crc32.ChecksumIEEE(payload[:length]) == binary.BigEndian.Uint32(rest[offset+4:offset+headerSize]) {
+			return ErrCorruptLog
+		}
+	}
+
+	return errTornRecord
+}
+
+// zeroedFrom checks whether every byte in the file from the offset to its size is zero
+func (seg *segment) zeroedFrom(offset, fileSize int64) (bool, error) {
+	reader := bufio.NewReader(io.NewSectionReader(seg.file, offset, fileSize-offset))
+	for {
+		b, err := reader.ReadByte()
+		if err == io.EOF {
+			return true, nil
+		} else if err != nil {
+			return false, err
+		}
+		if b != 0 {
+			return false, nil
+		}
+	}
+}
+
+// truncate cuts the segment file back to the end of its last good record
+func (seg *segment) truncate() error {
+	if err := seg.file.Truncate(seg.size); err != nil {
+		return err
+	}
+
+	return seg.file.Sync()
+}
+
+// append writes a record to the end of the segment, leaving the segment as it was if the write fails part way
+func (seg *segment) append(data []byte) (offset int64, err error) {
+	offset = seg.size
+	if _, err := seg.file.WriteAt(data, offset); err != nil {
+		seg.file.Truncate(offset) // best effort, a torn write is dealt with on the next open anyway
+		return 0, err
+	}
+
+	seg.size += int64(len(data))
+	return offset, nil
+}
+
+// read reads the record at a location back into a message
+func (seg *segment) read(loc location) (*repository.MessageEnvelope, error) {
+	data := make([]byte, loc.size)
+	if _, err := seg.file.ReadAt(data, loc.offset); err != nil {
+		return nil, err
+	}
+
+	return decode(data[:headerSize], data[headerSize:])
+}
+
+// unappend takes back the record appended at the offset, leaving the segment as it was before it was written
+func (seg *segment) unappend(offset int64) {
+	seg.size = offset
+	seg.file.Truncate(offset) // best effort, the next record is written over it anyway
+}
+
+func (seg *segment) sync() error {
+	return syncFile(seg.file)
+}
+
+// encode turns a message into a record with its header
+func encode(env *repository.MessageEnvelope) ([]byte, error) {
+	payload, err := json.Marshal(record{
+		ID:             env.ID,
+		StreamName:     env.StreamName,
+		MessageType:    env.MessageType,
+		Version:        env.Version,
+		GlobalPosition: env.GlobalPosition,
+		Data:           env.Data,
+		Metadata:       env.Metadata,
+		Time:           env.Time,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, headerSize+len(payload))
+	binary.BigEndian.PutUint32(data[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(data[4:8], crc32.ChecksumIEEE(payload))
+	copy(data[headerSize:], payload)
+
+	return data, nil
+}
+
+// decode checks a record against its header and turns it back into a message
+func decode(header, payload []byte) (*repository.MessageEnvelope, error) {
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, errTornRecord
+	}
+
+	var rec record
+	if err := json.Unmarshal(payload, &rec); err != nil {
+		return nil, err
+	}
+
+	return &repository.MessageEnvelope{
+		ID:             rec.ID,
+		StreamName:     rec.StreamName,
+		MessageType:    rec.MessageType,
+		Version:        rec.Version,
+		GlobalPosition: rec.GlobalPosition,
+		Data:           rec.Data,
+		Metadata:       rec.Metadata,
+		Time:           rec.Time,
+	}, nil
+}
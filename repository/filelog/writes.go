@@ -0,0 +1,107 @@
+package filelog
+
+import (
+	"context"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+)
+
+func (fl *fileLog) WriteMessage(ctx context.Context, msg *repository.MessageEnvelope) error {
+	return fl.writeMessageEitherWay(ctx, msg)
+}
+
+func (fl *fileLog) WriteMessageWithExpectedPosition(ctx context.Context, msg *repository.MessageEnvelope, position int64) error {
+	return fl.writeMessageEitherWay(ctx, msg, position)
+}
+
+// writeMessageEitherWay appends a message to the newest segment, starting a new segment first if that one is full
+func (fl *fileLog) writeMessageEitherWay(ctx context.Context, msg *repository.MessageEnvelope, position ...int64) error {
+	if msg == nil {
+		return repository.ErrNilMessage
+	}
+
+	if msg.ID == uuid.Nil {
+		return repository.ErrMessageNoID
+	}
+
+	if msg.StreamName == "" {
+		return repository.ErrInvalidStreamName
+	}
+
+	if len(position) > 0 && position[0] < -1 {
+		return repository.ErrInvalidPosition
+	}
+
+	fl.mutex.Lock()
+	defer fl.mutex.Unlock()
+
+	if fl.closed {
+		return ErrLogClosed
+	}
+
+	version := int64(len(fl.streams[msg.StreamName]) - 1)
+	if len(position) > 0 && position[0] != version {
+		return repository.ErrExpectedVersionFailed
+	}
+
+	if fl.ids[msg.ID] {
+		return repository.ErrDuplicateMessageID
+	}
+
+	env := *msg // make myself a copy
+	env.Version = version + 1
+	env.GlobalPosition = fl.globalPosition + 1
+	env.Time = time.Now().UTC()
+
+	data, err := encode(&env)
+	if err != nil {
+		return err
+	}
+
+	if newest := len(fl.segments) - 1; newest < 0 || (fl.segments[newest].size > 0 && fl.segments[newest].size+int64(len(data)) > fl.segmentSize) {
+		if err := fl.startSegment(env.GlobalPosition); err != nil {
+			fl.log.WithError(err).Error("Failure in filelog/writes.go::writeMessageEitherWay")
+			return err
+		}
+	}
+
+	seg := fl.segments[len(fl.segments)-1]
+	offset, err := seg.append(data)
+	if err != nil {
+		fl.log.WithError(err).Error("Failure in filelog/writes.go::writeMessageEitherWay")
+		return err
+	}
+
+	if fl.syncInterval == 0 && !fl.syncNever {
+		if err := seg.sync(); err != nil {
+			// the record can't be trusted to be there, so it's taken back and the next write goes where it was
+			seg.unappend(offset)
+			fl.log.WithError(err).Error("Failure in filelog/writes.go::writeMessageEitherWay")
+			return err
+		}
+	}
+
+	fl.index(&env, location{globalPosition: env.GlobalPosition, segment: len(fl.segments) - 1, offset: offset, size: int64(len(data))})
+	fl.log.Debugf("wrote successfully to stream %s", msg.StreamName)
+
+	return nil
+}
+
+// startSegment syncs the newest segment and starts a new one after it; the write lock must be held
+func (fl *fileLog) startSegment(globalPosition int64) error {
+	if len(fl.segments) > 0 {
+		if err := fl.segments[len(fl.segments)-1].sync(); err != nil {
+			return err
+		}
+	}
+
+	seg, err := openSegment(segmentName(fl.dir, globalPosition))
+	if err != nil {
+		return err
+	}
+
+	fl.segments = append(fl.segments, seg)
+	return nil
+}
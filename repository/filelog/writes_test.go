@@ -0,0 +1,71 @@
+package filelog
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileLogRepoTakesBackWritesThatFailToSync(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "gomessagestore")
+	if err != nil {
+		t.Fatalf("failed to create a temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	defer func() {
+		syncFile = (*os.File).Sync
+	}()
+
+	repo, err := NewFileLogRepository(dir, logrus.New())
+	if err != nil {
+		t.Fatalf("failed to open log: %s", err)
+	}
+
+	ctx := context.Background()
+	newMessage := func() *repository.MessageEnvelope {
+		return &repository.MessageEnvelope{
+			ID:          uuid.NewRandom(),
+			StreamName:  "thing-1",
+			MessageType: "Happened",
+			Data:        []byte(`{"some":"data"}`),
+		}
+	}
+	first, retried, last := newMessage(), newMessage(), newMessage()
+	assert.Nil(repo.WriteMessage(ctx, first))
+
+	syncErr := errors.New("disk failed")
+	syncFile = func(*os.File) error {
+		return syncErr
+	}
+	assert.Equal(syncErr, repo.WriteMessage(ctx, retried))
+	syncFile = (*os.File).Sync
+
+	// the failed write left nothing behind, so retrying it and writing after it carry on from the first message
+	assert.Nil(repo.WriteMessage(ctx, retried))
+	assert.Nil(repo.WriteMessage(ctx, last))
+	assert.Nil(repo.Close())
+
+	repo, err = NewFileLogRepository(dir, logrus.New())
+	if err != nil {
+		t.Fatalf("failed to reopen log: %s", err)
+	}
+	defer repo.Close()
+
+	msgs, err := repo.GetAllMessagesInStream(ctx, "thing-1", 0)
+	assert.Nil(err)
+	if assert.Len(msgs, 3) {
+		for i, msg := range []*repository.MessageEnvelope{first, retried, last} {
+			assert.Equal(msg.ID, msgs[i].ID)
+			assert.Equal(int64(i), msgs[i].Version)
+			assert.Equal(int64(i+1), msgs[i].GlobalPosition)
+		}
+	}
+}
@@ -2,171 +2,295 @@ package inmemory
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	. "github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
 )
 
+// inmemrepo is a reference implementation of Repository that behaves like the Eventide message store, safe for concurrent use
 type inmemrepo struct {
-	msgs []MessageEnvelope
+	mutex sync.RWMutex
+	msgs  []MessageEnvelope
 }
 
 //NewInMemoryRepository creates a Repistory filled with messages
 func NewInMemoryRepository(msgs []MessageEnvelope) Repository {
 	return &inmemrepo{
-		msgs: msgs,
+		msgs: append([]MessageEnvelope(nil), msgs...), // make myself a copy so the caller's slice can't change underneath us
 	}
 }
 
 //WriteMessage writes a message
 func (repo *inmemrepo) WriteMessage(ctx context.Context, message *MessageEnvelope) error {
-	newMessage := *message // make myself a copy
-	version := repo.findLastVersionForStream(newMessage.StreamName)
-	newMessage.Version = version + 1
-	globalPos := repo.findLastPosition()
-	newMessage.GlobalPosition = globalPos + 1
+	if err := validateMessage(message); err != nil {
+		return err
+	}
+
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	return repo.writeMessage(message)
+}
+
+//WriteMessageWithExpectedPosition writes a message with a position
+func (repo *inmemrepo) WriteMessageWithExpectedPosition(ctx context.Context, message *MessageEnvelope, position int64) error {
+	if err := validateMessage(message); err != nil {
+		return err
+	}
+
+	if position < -1 {
+		return ErrInvalidPosition
+	}
 
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	if version := repo.findLastVersionForStream(message.StreamName); version != position {
+		return ErrExpectedVersionFailed
+	}
+
+	return repo.writeMessage(message)
+}
+
+// writeMessage assigns the next version and global position to a copy of the message and stores it; the write lock must be held
+func (repo *inmemrepo) writeMessage(message *MessageEnvelope) error {
 	for _, msg := range repo.msgs {
 		if msg.ID == message.ID {
-			return errors.New("duplicate IDs are not allowed")
+			return ErrDuplicateMessageID
 		}
 	}
+
+	newMessage := *message // make myself a copy
+	newMessage.Version = repo.findLastVersionForStream(newMessage.StreamName) + 1
+	newMessage.GlobalPosition = repo.findLastPosition() + 1
+	newMessage.Time = time.Now().UTC() // the database sets the time, whatever the message says
+
 	repo.msgs = append(repo.msgs, newMessage)
 
 	return nil
 }
 
-//WriteMessageWithExpectedPosition writes a message with a position
-func (repo *inmemrepo) WriteMessageWithExpectedPosition(ctx context.Context, message *MessageEnvelope, position int64) error {
-	version := repo.findLastVersionForStream(message.StreamName)
-	if version != position {
-		return errors.New(fmt.Sprintf("position incorrect. should be %d", version))
+//GetAllMessagesInStream gets all messages in a stream
+func (repo *inmemrepo) GetAllMessagesInStream(ctx context.Context, streamName string, batchSize int) ([]*MessageEnvelope, error) {
+	return repo.GetAllMessagesInStreamSince(ctx, streamName, 0, batchSize)
+}
+
+//GetAllMessagesInStreamSince gets all messages in a stream with a version greater than or equal to the one provided, of the types when there are any
+func (repo *inmemrepo) GetAllMessagesInStreamSince(ctx context.Context, streamName string, version int64, batchSize int, types ...string) ([]*MessageEnvelope, error) {
+	if streamName == "" {
+		return nil, ErrInvalidStreamName
+	}
+	if batchSize < 0 {
+		return nil, ErrNegativeBatchSize
 	}
 
-	return repo.WriteMessage(ctx, message)
+	return repo.find(batchSize, func(msg *MessageEnvelope) bool {
+		return msg.StreamName == streamName && msg.Version >= version && MatchesType(msg.MessageType, types)
+	}), nil
 }
 
-//GetAllMessagesInStream gets all messages in a stream
-func (repo *inmemrepo) GetAllMessagesInStream(ctx context.Context, streamName string, batchSize int) ([]*MessageEnvelope, error) {
-	msgs := make([]*MessageEnvelope, 0, batchSize)
-
-	for _, msg := range repo.msgs {
-		if msg.StreamName == streamName {
-			newMessage := msg // make a copy so we don't have strangeness with slices of pointers
-			msgs = append(msgs, &newMessage)
-		}
-		if len(msgs) == batchSize {
-			return msgs, nil
-		}
+//GetAllMessagesInStreamBackward gets the messages in a stream with a version less than or equal to the one provided, newest first
+func (repo *inmemrepo) GetAllMessagesInStreamBackward(ctx context.Context, streamName string, version int64, batchSize int) ([]*MessageEnvelope, error) {
+	if streamName == "" {
+		return nil, ErrInvalidStreamName
+	}
+	if batchSize < 0 {
+		return nil, ErrNegativeBatchSize
 	}
 
-	return msgs, nil
+	return repo.findBackward(batchSize, func(msg *MessageEnvelope) bool {
+		return msg.StreamName == streamName && msg.Version <= version
+	}), nil
 }
 
-//GetAllMessagesInStreamSince gets all messages in a streams since position
-func (repo *inmemrepo) GetAllMessagesInStreamSince(ctx context.Context, streamName string, version int64, batchSize int) ([]*MessageEnvelope, error) {
-	msgs := make([]*MessageEnvelope, 0, batchSize)
+//GetLastMessageInStream gets the last message in a stream
+func (repo *inmemrepo) GetLastMessageInStream(ctx context.Context, streamName string) (*MessageEnvelope, error) {
+	if streamName == "" {
+		return nil, ErrInvalidStreamName
+	}
 
-	atPos := false
-	for _, msg := range repo.msgs {
-		if msg.Version >= version {
-			atPos = true
-		}
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
 
-		if atPos {
-			if msg.StreamName == streamName {
-				newMessage := msg // make a copy so we don't have strangeness with slices of pointers
-				msgs = append(msgs, &newMessage)
-			}
-			if len(msgs) == batchSize {
-				return msgs, nil
-			}
+	for i := len(repo.msgs) - 1; i >= 0; i-- {
+		if repo.msgs[i].StreamName == streamName {
+			foundMsg := repo.msgs[i] // make a copy so nobody can change what we have stored
+			return &foundMsg, nil
 		}
 	}
 
-	return msgs, nil
+	return nil, nil
 }
 
-//GetLastMessageInStream gets the last message in a stream
-func (repo *inmemrepo) GetLastMessageInStream(ctx context.Context, streamName string) (foundMsg *MessageEnvelope, err error) {
+//GetStreamVersion gets the version of the last message in a stream, -1 when it has none
+func (repo *inmemrepo) GetStreamVersion(ctx context.Context, streamName string) (int64, error) {
+	if streamName == "" {
+		return 0, ErrInvalidStreamName
+	}
+
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+
+	return repo.findLastVersionForStream(streamName), nil
+}
+
+//GetStreamVersionAtTime gets the version of the first message in a stream written at or after a time
+func (repo *inmemrepo) GetStreamVersionAtTime(ctx context.Context, streamName string, t time.Time) (int64, error) {
+	if streamName == "" {
+		return 0, ErrInvalidStreamName
+	}
+
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+
 	for _, msg := range repo.msgs {
-		if msg.StreamName == streamName {
-			newMsg := msg // make a copy so we don't just reassign based on the next item in the loop
-			foundMsg = &newMsg
+		if msg.StreamName == streamName && !msg.Time.Before(t) {
+			return msg.Version, nil
 		}
 	}
 
-	return
+	return repo.findLastVersionForStream(streamName) + 1, nil
 }
 
 //GetAllMessagesInCategory gets all messages in a category
 func (repo *inmemrepo) GetAllMessagesInCategory(ctx context.Context, category string, batchSize int) ([]*MessageEnvelope, error) {
-	msgs := make([]*MessageEnvelope, 0, batchSize)
+	return repo.GetAllMessagesInCategorySince(ctx, category, 0, batchSize)
+}
+
+//GetAllMessagesInCategorySince gets all messages in a category with a global position greater than or equal to the one provided, of the types when there are any
+func (repo *inmemrepo) GetAllMessagesInCategorySince(ctx context.Context, category string, globalPosition int64, batchSize int, types ...string) ([]*MessageEnvelope, error) {
+	if category == "" {
+		return nil, ErrBlankCategory
+	}
+	if batchSize < 0 {
+		return nil, ErrNegativeBatchSize
+	}
+	if strings.Contains(category, "-") {
+		return nil, ErrInvalidCategory
+	}
+
+	return repo.find(batchSize, func(msg *MessageEnvelope) bool {
+		return Category(msg.StreamName) == category && msg.GlobalPosition >= globalPosition && MatchesType(msg.MessageType, types)
+	}), nil
+}
+
+//GetAllMessagesInCategoryBackward gets the messages in a category with a global position less than or equal to the one provided, newest first
+func (repo *inmemrepo) GetAllMessagesInCategoryBackward(ctx context.Context, category string, globalPosition int64, batchSize int) ([]*MessageEnvelope, error) {
+	if category == "" {
+		return nil, ErrBlankCategory
+	}
+	if batchSize < 0 {
+		return nil, ErrNegativeBatchSize
+	}
+	if strings.Contains(category, "-") {
+		return nil, ErrInvalidCategory
+	}
+
+	return repo.findBackward(batchSize, func(msg *MessageEnvelope) bool {
+		return Category(msg.StreamName) == category && msg.GlobalPosition <= globalPosition
+	}), nil
+}
+
+//GetAllMessagesSince gets messages from every category that passes the filter, with a global position greater than or equal to the one provided,
+//of the types when there are any
+func (repo *inmemrepo) GetAllMessagesSince(ctx context.Context, globalPosition int64, batchSize int, filter CategoryFilter, types ...string) ([]*MessageEnvelope, error) {
+	if batchSize < 0 {
+		return nil, ErrNegativeBatchSize
+	}
+
+	return repo.find(batchSize, func(msg *MessageEnvelope) bool {
+		return msg.GlobalPosition >= globalPosition && filter.Matches(Category(msg.StreamName)) && MatchesType(msg.MessageType, types)
+	}), nil
+}
+
+//GetGlobalPositionAtTime gets the global position of the first message written at or after a time
+func (repo *inmemrepo) GetGlobalPositionAtTime(ctx context.Context, t time.Time) (int64, error) {
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
 
 	for _, msg := range repo.msgs {
-		if categoryMatches(msg.StreamName, category) {
-			newMessage := msg // make a copy so we don't just reassign based on the next item in the loop
+		if !msg.Time.Before(t) {
+			return msg.GlobalPosition, nil
+		}
+	}
+
+	return repo.findLastPosition() + 1, nil
+}
+
+// find returns copies of up to batchSize messages that match, in global position order; a batchSize of 0 means no limit
+func (repo *inmemrepo) find(batchSize int, matches func(msg *MessageEnvelope) bool) []*MessageEnvelope {
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+
+	msgs := make([]*MessageEnvelope, 0, batchSize)
+	for i := range repo.msgs {
+		if matches(&repo.msgs[i]) {
+			newMessage := repo.msgs[i] // make a copy so we don't have strangeness with slices of pointers
 			msgs = append(msgs, &newMessage)
 		}
-		if len(msgs) == batchSize {
-			return msgs, nil
+		if batchSize > 0 && len(msgs) == batchSize {
+			break
 		}
 	}
 
-	return msgs, nil
+	return msgs
 }
 
-//GetAllMessagesInCategorySince gets all messages in a category since a position
-func (repo *inmemrepo) GetAllMessagesInCategorySince(ctx context.Context, category string, globalPosition int64, batchSize int) ([]*MessageEnvelope, error) {
-	msgs := make([]*MessageEnvelope, 0, batchSize)
+// findBackward is find, newest first
+func (repo *inmemrepo) findBackward(batchSize int, matches func(msg *MessageEnvelope) bool) []*MessageEnvelope {
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
 
-	atPos := false
-	for _, msg := range repo.msgs {
-		if msg.GlobalPosition >= globalPosition {
-			atPos = true
+	msgs := make([]*MessageEnvelope, 0, batchSize)
+	for i := len(repo.msgs) - 1; i >= 0; i-- {
+		if matches(&repo.msgs[i]) {
+			newMessage := repo.msgs[i] // make a copy so we don't have strangeness with slices of pointers
+			msgs = append(msgs, &newMessage)
 		}
-
-		if atPos {
-			if categoryMatches(msg.StreamName, category) {
-				newMessage := msg // make a copy so we don't just reassign based on the next item in the loop
-				msgs = append(msgs, &newMessage)
-			}
-			if len(msgs) == batchSize {
-				return msgs, nil
-			}
+		if batchSize > 0 && len(msgs) == batchSize {
+			break
 		}
 	}
 
-	return msgs, nil
+	return msgs
 }
 
+// findLastVersionForStream returns the version of the last message in the stream, -1 when it has none; the lock must be held
 func (repo *inmemrepo) findLastVersionForStream(stream string) int64 {
-	var version int64
-	version = -1
-	for _, msg := range repo.msgs {
-		if msg.StreamName == stream {
-			version = msg.Version
+	for i := len(repo.msgs) - 1; i >= 0; i-- {
+		if repo.msgs[i].StreamName == stream {
+			return repo.msgs[i].Version
 		}
 	}
 
-	return version
+	return -1
 }
 
+// findLastPosition returns the global position of the last message written, 0 when there are none so the first message is at 1 like Postgres; the lock must be held
 func (repo *inmemrepo) findLastPosition() int64 {
 	if len(repo.msgs) > 0 {
 		return repo.msgs[len(repo.msgs)-1].GlobalPosition
 	}
 
-	return -1
+	return 0
 }
 
-func categoryMatches(streamName string, category string) bool {
-	streamPieces := strings.Split(streamName, "-")
-	if len(streamPieces) == 0 {
-		return false
+// validateMessage performs the same checks on a message as the Postgres repository
+func validateMessage(message *MessageEnvelope) error {
+	if message == nil {
+		return ErrNilMessage
 	}
 
-	return streamPieces[0] == category
+	if message.ID == uuid.Nil {
+		return ErrMessageNoID
+	}
+
+	if message.StreamName == "" {
+		return ErrInvalidStreamName
+	}
+
+	return nil
 }
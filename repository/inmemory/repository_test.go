@@ -6,6 +6,7 @@ import (
 
 	. "github.com/blackhatbrigade/gomessagestore/repository"
 	. "github.com/blackhatbrigade/gomessagestore/repository/inmemory"
+	"github.com/blackhatbrigade/gomessagestore/repository/repositorytest"
 	"github.com/blackhatbrigade/gomessagestore/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -146,6 +147,7 @@ func TestInMemRepository(t *testing.T) {
 
 	//get last from stream a
 	msg, err = repo.GetLastMessageInStream(ctx, "A-123")
+	assert.False(msg.Time.IsZero()) // the time is set when the message is written
 	assert.Equal(&MessageEnvelope{
 		ID:             newID,
 		StreamName:     "A-123",
@@ -153,6 +155,7 @@ func TestInMemRepository(t *testing.T) {
 		MessageType:    "uh",
 		Version:        7,
 		GlobalPosition: 109,
+		Time:           msg.Time,
 	}, msg)
 	assert.Nil(err)
 
@@ -189,6 +192,7 @@ func TestInMemRepository(t *testing.T) {
 	lastMsg := msgs[len(msgs)-1]
 	msg.GlobalPosition = 110 // this will be what it is after we write it
 	msg.Version = 0          // this will be what it is after we write it
+	msg.Time = lastMsg.Time  // as will this
 	assert.Equal(msg, lastMsg)
 
 	//write a command at position
@@ -236,3 +240,9 @@ func TestInMemRepositoryWriteFirstMessageAtPosition(t *testing.T) {
 	err := repo.WriteMessageWithExpectedPosition(ctx, cmd, -1)
 	assert.Nil(err)
 }
+
+func TestInMemRepositoryConformance(t *testing.T) {
+	repositorytest.RunConformance(t, func(t *testing.T) Repository {
+		return NewInMemoryRepository(nil)
+	})
+}
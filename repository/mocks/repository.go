@@ -9,6 +9,7 @@ import (
 	repository "github.com/blackhatbrigade/gomessagestore/repository"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockRepository is a mock of Repository interface
@@ -49,19 +50,39 @@ func (mr *MockRepositoryMockRecorder) GetAllMessagesInCategory(arg0, arg1, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInCategory", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInCategory), arg0, arg1, arg2)
 }
 
+// GetAllMessagesInCategoryBackward mocks base method
+func (m *MockRepository) GetAllMessagesInCategoryBackward(arg0 context.Context, arg1 string, arg2 int64, arg3 int) ([]*repository.MessageEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllMessagesInCategoryBackward", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*repository.MessageEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllMessagesInCategoryBackward indicates an expected call of GetAllMessagesInCategoryBackward
+func (mr *MockRepositoryMockRecorder) GetAllMessagesInCategoryBackward(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInCategoryBackward", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInCategoryBackward), arg0, arg1, arg2, arg3)
+}
+
 // GetAllMessagesInCategorySince mocks base method
-func (m *MockRepository) GetAllMessagesInCategorySince(arg0 context.Context, arg1 string, arg2 int64, arg3 int) ([]*repository.MessageEnvelope, error) {
+func (m *MockRepository) GetAllMessagesInCategorySince(arg0 context.Context, arg1 string, arg2 int64, arg3 int, arg4 ...string) ([]*repository.MessageEnvelope, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllMessagesInCategorySince", arg0, arg1, arg2, arg3)
+	varargs := []interface{}{arg0, arg1, arg2, arg3}
+	for _, a := range arg4 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAllMessagesInCategorySince", varargs...)
 	ret0, _ := ret[0].([]*repository.MessageEnvelope)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllMessagesInCategorySince indicates an expected call of GetAllMessagesInCategorySince
-func (mr *MockRepositoryMockRecorder) GetAllMessagesInCategorySince(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetAllMessagesInCategorySince(arg0, arg1, arg2, arg3 interface{}, arg4 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInCategorySince", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInCategorySince), arg0, arg1, arg2, arg3)
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3}, arg4...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInCategorySince", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInCategorySince), varargs...)
 }
 
 // GetAllMessagesInStream mocks base method
@@ -79,19 +100,74 @@ func (mr *MockRepositoryMockRecorder) GetAllMessagesInStream(arg0, arg1, arg2 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInStream", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInStream), arg0, arg1, arg2)
 }
 
+// GetAllMessagesInStreamBackward mocks base method
+func (m *MockRepository) GetAllMessagesInStreamBackward(arg0 context.Context, arg1 string, arg2 int64, arg3 int) ([]*repository.MessageEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllMessagesInStreamBackward", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*repository.MessageEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllMessagesInStreamBackward indicates an expected call of GetAllMessagesInStreamBackward
+func (mr *MockRepositoryMockRecorder) GetAllMessagesInStreamBackward(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInStreamBackward", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInStreamBackward), arg0, arg1, arg2, arg3)
+}
+
 // GetAllMessagesInStreamSince mocks base method
-func (m *MockRepository) GetAllMessagesInStreamSince(arg0 context.Context, arg1 string, arg2 int64, arg3 int) ([]*repository.MessageEnvelope, error) {
+func (m *MockRepository) GetAllMessagesInStreamSince(arg0 context.Context, arg1 string, arg2 int64, arg3 int, arg4 ...string) ([]*repository.MessageEnvelope, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllMessagesInStreamSince", arg0, arg1, arg2, arg3)
+	varargs := []interface{}{arg0, arg1, arg2, arg3}
+	for _, a := range arg4 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAllMessagesInStreamSince", varargs...)
 	ret0, _ := ret[0].([]*repository.MessageEnvelope)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllMessagesInStreamSince indicates an expected call of GetAllMessagesInStreamSince
-func (mr *MockRepositoryMockRecorder) GetAllMessagesInStreamSince(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetAllMessagesInStreamSince(arg0, arg1, arg2, arg3 interface{}, arg4 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInStreamSince", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInStreamSince), arg0, arg1, arg2, arg3)
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3}, arg4...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesInStreamSince", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesInStreamSince), varargs...)
+}
+
+// GetAllMessagesSince mocks base method
+func (m *MockRepository) GetAllMessagesSince(arg0 context.Context, arg1 int64, arg2 int, arg3 repository.CategoryFilter, arg4 ...string) ([]*repository.MessageEnvelope, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2, arg3}
+	for _, a := range arg4 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAllMessagesSince", varargs...)
+	ret0, _ := ret[0].([]*repository.MessageEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllMessagesSince indicates an expected call of GetAllMessagesSince
+func (mr *MockRepositoryMockRecorder) GetAllMessagesSince(arg0, arg1, arg2, arg3 interface{}, arg4 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3}, arg4...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMessagesSince", reflect.TypeOf((*MockRepository)(nil).GetAllMessagesSince), varargs...)
+}
+
+// GetGlobalPositionAtTime mocks base method
+func (m *MockRepository) GetGlobalPositionAtTime(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGlobalPositionAtTime", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlobalPositionAtTime indicates an expected call of GetGlobalPositionAtTime
+func (mr *MockRepositoryMockRecorder) GetGlobalPositionAtTime(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalPositionAtTime", reflect.TypeOf((*MockRepository)(nil).GetGlobalPositionAtTime), arg0, arg1)
 }
 
 // GetLastMessageInStream mocks base method
@@ -109,6 +185,36 @@ func (mr *MockRepositoryMockRecorder) GetLastMessageInStream(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastMessageInStream", reflect.TypeOf((*MockRepository)(nil).GetLastMessageInStream), arg0, arg1)
 }
 
+// GetStreamVersion mocks base method
+func (m *MockRepository) GetStreamVersion(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamVersion", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamVersion indicates an expected call of GetStreamVersion
+func (mr *MockRepositoryMockRecorder) GetStreamVersion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamVersion", reflect.TypeOf((*MockRepository)(nil).GetStreamVersion), arg0, arg1)
+}
+
+// GetStreamVersionAtTime mocks base method
+func (m *MockRepository) GetStreamVersionAtTime(arg0 context.Context, arg1 string, arg2 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamVersionAtTime", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamVersionAtTime indicates an expected call of GetStreamVersionAtTime
+func (mr *MockRepositoryMockRecorder) GetStreamVersionAtTime(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamVersionAtTime", reflect.TypeOf((*MockRepository)(nil).GetStreamVersionAtTime), arg0, arg1, arg2)
+}
+
 // WriteMessage mocks base method
 func (m *MockRepository) WriteMessage(arg0 context.Context, arg1 *repository.MessageEnvelope) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,68 @@
+package ndjson
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/blackhatbrigade/gomessagestore/internal/jsonenvelope"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+)
+
+// ExportStream writes every message in a stream to w, returning how many were written
+func ExportStream(ctx context.Context, repo repository.Repository, w io.Writer, streamName string) (int, error) {
+	return export(w, func(last *repository.MessageEnvelope) ([]*repository.MessageEnvelope, error) {
+		var version int64
+		if last != nil {
+			version = last.Version + 1
+		}
+		return repo.GetAllMessagesInStreamSince(ctx, streamName, version, batchSize)
+	})
+}
+
+// ExportCategory writes every message in a category to w, in global position order, returning how many were written
+func ExportCategory(ctx context.Context, repo repository.Repository, w io.Writer, category string) (int, error) {
+	return export(w, func(last *repository.MessageEnvelope) ([]*repository.MessageEnvelope, error) {
+		var position int64
+		if last != nil {
+			position = last.GlobalPosition + 1
+		}
+		return repo.GetAllMessagesInCategorySince(ctx, category, position, batchSize)
+	})
+}
+
+// ExportAll writes every message in the repository to w, in global position order, returning how many were written
+func ExportAll(ctx context.Context, repo repository.Repository, w io.Writer) (int, error) {
+	return export(w, func(last *repository.MessageEnvelope) ([]*repository.MessageEnvelope, error) {
+		var position int64
+		if last != nil {
+			position = last.GlobalPosition + 1
+		}
+		return repo.GetAllMessagesSince(ctx, position, batchSize, repository.CategoryFilter{})
+	})
+}
+
+// export writes batches of messages until next has no more, next being handed the last message written so it can carry on after it
+func export(w io.Writer, next func(last *repository.MessageEnvelope) ([]*repository.MessageEnvelope, error)) (int, error) {
+	encoder := json.NewEncoder(w) // one value per line
+	var written int
+	var last *repository.MessageEnvelope
+
+	for {
+		msgs, err := next(last)
+		if err != nil {
+			return written, err
+		}
+		if len(msgs) == 0 {
+			return written, nil
+		}
+
+		for _, msg := range msgs {
+			if err := encoder.Encode(jsonenvelope.FromEnvelope(msg)); err != nil {
+				return written, err
+			}
+			written++
+		}
+		last = msgs[len(msgs)-1]
+	}
+}
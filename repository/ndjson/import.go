@@ -0,0 +1,116 @@
+package ndjson
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/internal/jsonenvelope"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+)
+
+// ImportOption changes how messages are imported
+type ImportOption func(imp *importer)
+
+// ImportResult counts what happened to the messages read by Import
+type ImportResult struct {
+	Written int // messages written to the repository
+	Skipped int // messages that were already in the repository, with SkipExisting
+}
+
+type importer struct {
+	preserveIDs  bool
+	skipExisting bool
+	categories   map[string]string // categories to rename, from the exported category to the imported one
+}
+
+// PreserveIDs keeps the IDs messages were exported with, instead of giving each a new one
+func PreserveIDs() ImportOption {
+	return func(imp *importer) {
+		imp.preserveIDs = true
+	}
+}
+
+// SkipExisting skips messages whose ID is already in the repository instead of failing, so an import can be run again.
+// It needs PreserveIDs, as messages given new IDs are never already there; Import returns ErrSkipExistingWithoutPreserveIDs otherwise.
+func SkipExisting() ImportOption {
+	return func(imp *importer) {
+		imp.skipExisting = true
+	}
+}
+
+// RemapCategory imports the streams in one category into another, keeping the rest of each stream name.
+// Categories have to match exactly, so "account" doesn't remap "account:command".
+func RemapCategory(from, to string) ImportOption {
+	return func(imp *importer) {
+		imp.categories[from] = to
+	}
+}
+
+// Import writes every message read from r to the repository, in the order they were exported
+func Import(ctx context.Context, repo repository.Repository, r io.Reader, opts ...ImportOption) (ImportResult, error) {
+	imp := &importer{
+		categories: make(map[string]string),
+	}
+	for _, option := range opts {
+		option(imp)
+	}
+
+	if imp.skipExisting && !imp.preserveIDs {
+		return ImportResult{}, ErrSkipExistingWithoutPreserveIDs
+	}
+
+	for _, to := range imp.categories {
+		if to == "" {
+			return ImportResult{}, repository.ErrBlankCategory
+		}
+		if repository.Category(to) != to {
+			return ImportResult{}, repository.ErrInvalidCategory
+		}
+	}
+
+	var result ImportResult
+	decoder := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var rec jsonenvelope.Record
+		if err := decoder.Decode(&rec); err == io.EOF {
+			return result, nil
+		} else if err != nil {
+			return result, fmt.Errorf("ndjson: message %d: %w", line, err)
+		}
+
+		env := imp.prepare(rec.ToEnvelope())
+		if err := repo.WriteMessage(ctx, env); errors.Is(err, repository.ErrDuplicateMessageID) && imp.skipExisting {
+			result.Skipped++
+			continue
+		} else if err != nil {
+			return result, fmt.Errorf("ndjson: message %d: %w", line, err)
+		}
+		result.Written++
+	}
+}
+
+// prepare applies the import options to a message, leaving the positions and time for the repository to assign
+func (imp *importer) prepare(env *repository.MessageEnvelope) *repository.MessageEnvelope {
+	if !imp.preserveIDs {
+		env.ID = uuid.NewRandom()
+	}
+
+	category := repository.Category(env.StreamName)
+	if to, ok := imp.categories[category]; ok {
+		env.StreamName = to + env.StreamName[len(category):]
+		if env.StreamCategory != "" {
+			env.StreamCategory = repository.Category(env.StreamName) // not taken from the line, which could disagree with its stream name
+		}
+	}
+
+	env.Version = 0
+	env.GlobalPosition = 0
+	env.Time = time.Time{}
+
+	return env
+}
@@ -0,0 +1,15 @@
+// Package ndjson dumps messages from any repository as newline delimited JSON, one envelope per line, and loads them back into
+// any repository. It's meant for moving messages between environments and for golden files in tests.
+//
+// Every field of a MessageEnvelope is exported, but positions, versions and times are assigned by the repository messages are
+// imported into, so they're only kept for reference. Messages are imported in the order they were exported, so each stream's
+// messages keep their order.
+package ndjson
+
+import "github.com/blackhatbrigade/gomessagestore/repository"
+
+//ErrSkipExistingWithoutPreserveIDs is returned by Import when SkipExisting is used without PreserveIDs
+const ErrSkipExistingWithoutPreserveIDs = repository.Error("SkipExisting needs PreserveIDs, as messages given new IDs are never already there")
+
+// batchSize is how many messages are read from a repository at a time while exporting
+const batchSize = 1000
@@ -0,0 +1,201 @@
+package ndjson_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/repository/inmemory"
+	. "github.com/blackhatbrigade/gomessagestore/repository/ndjson"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	entityA = uuid.NewRandom()
+	entityB = uuid.NewRandom()
+)
+
+// newRepo makes a repository holding two account streams and a command stream
+func newRepo(t *testing.T) Repository {
+	repo := inmemory.NewInMemoryRepository(nil)
+	writes := []struct {
+		stream   string
+		category string
+		entity   uuid.UUID
+		metadata []byte
+	}{
+		{"account-" + entityA.String(), "account", entityA, []byte(`{"userId":"1"}`)},
+		{"account:command-" + entityB.String(), "account:command", entityB, nil},
+		{"account-" + entityB.String(), "account", entityB, nil},
+		{"account-" + entityA.String(), "account", entityA, nil},
+	}
+	for _, write := range writes {
+		err := repo.WriteMessage(context.Background(), &MessageEnvelope{
+			ID:             uuid.NewRandom(),
+			EntityID:       write.entity,
+			StreamName:     write.stream,
+			StreamCategory: write.category,
+			MessageType:    "Opened",
+			Data:           []byte(`{"balance":10}`),
+			Metadata:       write.metadata,
+		})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+	}
+
+	return repo
+}
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+	repo := newRepo(t)
+
+	var buf bytes.Buffer
+	count, err := ExportStream(ctx, repo, &buf, "account-"+entityA.String())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"data":{"balance":10}`)
+	assert.Contains(t, lines[0], `"metadata":{"userId":"1"}`)
+	assert.Contains(t, lines[0], `"globalPosition":1`)
+	assert.Contains(t, lines[1], `"metadata":null`)
+	assert.Contains(t, lines[1], `"version":1`)
+
+	buf.Reset()
+	count, err = ExportCategory(ctx, repo, &buf, "account")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+	assert.NotContains(t, buf.String(), "account:command")
+
+	buf.Reset()
+	count, err = ExportAll(ctx, repo, &buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, count)
+
+	buf.Reset()
+	count, err = ExportStream(ctx, repo, &buf, "account-"+uuid.NewRandom().String())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, "", buf.String())
+}
+
+func TestImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := newRepo(t)
+
+	var buf bytes.Buffer
+	_, err := ExportAll(ctx, source, &buf)
+	assert.Nil(t, err)
+
+	target := inmemory.NewInMemoryRepository(nil)
+	result, err := Import(ctx, target, bytes.NewReader(buf.Bytes()), PreserveIDs())
+	assert.Nil(t, err)
+	assert.Equal(t, ImportResult{Written: 4}, result)
+
+	expected, err := source.GetAllMessagesSince(ctx, 0, 10, CategoryFilter{})
+	assert.Nil(t, err)
+	imported, err := target.GetAllMessagesSince(ctx, 0, 10, CategoryFilter{})
+	assert.Nil(t, err)
+	if assert.Len(t, imported, len(expected)) {
+		for i := range expected {
+			assert.Equal(t, expected[i].ID, imported[i].ID)
+			assert.Equal(t, expected[i].EntityID, imported[i].EntityID)
+			assert.Equal(t, expected[i].StreamName, imported[i].StreamName)
+			assert.Equal(t, expected[i].StreamCategory, imported[i].StreamCategory)
+			assert.Equal(t, expected[i].MessageType, imported[i].MessageType)
+			assert.Equal(t, expected[i].Version, imported[i].Version)
+			assert.Equal(t, expected[i].GlobalPosition, imported[i].GlobalPosition)
+			assert.Equal(t, expected[i].Data, imported[i].Data)
+			assert.Equal(t, expected[i].Metadata, imported[i].Metadata)
+		}
+	}
+
+	// running it again only works when messages already there are skipped
+	_, err = Import(ctx, target, bytes.NewReader(buf.Bytes()), PreserveIDs())
+	assert.True(t, errors.Is(err, ErrDuplicateMessageID))
+	assert.Contains(t, err.Error(), "message 1")
+
+	_, err = Import(ctx, target, bytes.NewReader(buf.Bytes()), SkipExisting())
+	assert.Equal(t, ErrSkipExistingWithoutPreserveIDs, err)
+
+	result, err = Import(ctx, target, bytes.NewReader(buf.Bytes()), PreserveIDs(), SkipExisting())
+	assert.Nil(t, err)
+	assert.Equal(t, ImportResult{Skipped: 4}, result)
+}
+
+func TestImportAssignsNewIDs(t *testing.T) {
+	ctx := context.Background()
+	source := newRepo(t)
+
+	var buf bytes.Buffer
+	_, err := ExportAll(ctx, source, &buf)
+	assert.Nil(t, err)
+
+	target := newRepo(t)
+	result, err := Import(ctx, target, bytes.NewReader(buf.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, ImportResult{Written: 4}, result)
+
+	msgs, err := target.GetAllMessagesInStream(ctx, "account-"+entityA.String(), 10)
+	assert.Nil(t, err)
+	assert.Len(t, msgs, 4)
+}
+
+func TestImportRemapsCategories(t *testing.T) {
+	ctx := context.Background()
+	source := newRepo(t)
+
+	var buf bytes.Buffer
+	_, err := ExportAll(ctx, source, &buf)
+	assert.Nil(t, err)
+
+	target := inmemory.NewInMemoryRepository(nil)
+	_, err = Import(ctx, target, bytes.NewReader(buf.Bytes()), RemapCategory("account", "ledger"))
+	assert.Nil(t, err)
+
+	msgs, err := target.GetAllMessagesInStream(ctx, "ledger-"+entityA.String(), 10)
+	assert.Nil(t, err)
+	if assert.Len(t, msgs, 2) {
+		assert.Equal(t, "ledger", msgs[0].StreamCategory)
+		assert.Equal(t, entityA, msgs[0].EntityID)
+	}
+
+	// only the exact category is remapped
+	msgs, err = target.GetAllMessagesInStream(ctx, "account:command-"+entityB.String(), 10)
+	assert.Nil(t, err)
+	assert.Len(t, msgs, 1)
+
+	_, err = Import(ctx, target, bytes.NewReader(buf.Bytes()), RemapCategory("account", "led-ger"))
+	assert.Equal(t, ErrInvalidCategory, err)
+
+	// a stream category that disagrees with the stream name is taken from the remapped stream name
+	entityC := uuid.NewRandom()
+	input := `{"id":"` + uuid.NewRandom().String() + `","streamName":"account-` + entityC.String() + `","streamCategory":"a","messageType":"Opened","data":{}}`
+	_, err = Import(ctx, target, strings.NewReader(input), RemapCategory("account", "ledger"))
+	assert.Nil(t, err)
+	msgs, err = target.GetAllMessagesInStream(ctx, "ledger-"+entityC.String(), 10)
+	assert.Nil(t, err)
+	if assert.Len(t, msgs, 1) {
+		assert.Equal(t, "ledger", msgs[0].StreamCategory)
+	}
+}
+
+func TestImportReportsBadLines(t *testing.T) {
+	ctx := context.Background()
+	target := inmemory.NewInMemoryRepository(nil)
+
+	input := `{"id":"` + uuid.NewRandom().String() + `","streamName":"account-` + entityA.String() + `","streamCategory":"account","messageType":"Opened","data":{}}
+{"id": not json}
+`
+	result, err := Import(ctx, target, strings.NewReader(input))
+	assert.Equal(t, ImportResult{Written: 1}, result)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "message 2")
+	}
+}
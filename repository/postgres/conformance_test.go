@@ -0,0 +1,51 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	. "github.com/blackhatbrigade/gomessagestore/repository/postgres"
+	"github.com/blackhatbrigade/gomessagestore/repository/repositorytest"
+	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// TestPostgresRepoConformance installs the message store in a real database, found with GMS_TEST_POSTGRES_DSN, and runs the
+// conformance tests against it. Every message in the database is deleted before each test, so never point it at a database you
+// care about. The DSN needs message_store on the search path, for example:
+//
+//	postgres://postgres@localhost/gms_test?sslmode=disable&search_path=message_store,public
+func TestPostgresRepoConformance(t *testing.T) {
+	dsn := os.Getenv("GMS_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("GMS_TEST_POSTGRES_DSN is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %s", err)
+	}
+	defer db.Close()
+
+	for i := 0; i < 2; i++ { // installing again changes nothing
+		if err := Install(context.Background(), db); err != nil {
+			t.Fatalf("failed to install the message store: %s", err)
+		}
+	}
+
+	version, err := InstalledVersion(context.Background(), db)
+	if err != nil || version != LatestSchemaVersion {
+		t.Fatalf("expected version %d to be installed, found %d (%v)", LatestSchemaVersion, version, err)
+	}
+
+	repositorytest.RunConformance(t, func(t *testing.T) repository.Repository {
+		if _, err := db.Exec("TRUNCATE message_store.messages RESTART IDENTITY"); err != nil {
+			t.Fatalf("failed to clear out messages: %s", err)
+		}
+
+		return NewPostgresRepository(db, logrus.New())
+	})
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+)
+
+// baselineVersion is the migration that creates the Eventide schema, which a database installed by Eventide already has
+const baselineVersion = 1
+
+// migrationLock is the advisory lock held while migrating, so two services starting at once don't both try
+const migrationLock = 7349527061265536147
+
+//LatestSchemaVersion is the version of the message store schema that Install and Migrate bring a database up to
+var LatestSchemaVersion = migrations[len(migrations)-1].version
+
+//Install creates the message store schema, or upgrades it to LatestSchemaVersion, so services and tests can provision their own database.
+//Everything is created in the message_store schema, like Eventide, so connections need message_store on their search_path
+//(lib/pq takes search_path=message_store,public in the connection string).
+func Install(ctx context.Context, db *sql.DB) error {
+	_, err := Migrate(ctx, db)
+	return err
+}
+
+//Migrate runs every migration the database hasn't had yet in a single transaction, returning the version it's now at.
+//A database that already has the Eventide functions, but was never migrated by this package, is marked as having the baseline
+//Eventide schema without changing it, and every migration after that is run.
+func Migrate(ctx context.Context, db *sql.DB) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		logrus.WithError(err).Error("Failure in postgres/install.go::Migrate")
+		return 0, err
+	}
+	defer tx.Rollback() // does nothing once committed
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", int64(migrationLock)); err != nil {
+		logrus.WithError(err).Error("Failure in postgres/install.go::Migrate")
+		return 0, err
+	}
+
+	var alreadyInstalled bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM pg_proc JOIN pg_namespace ON pg_namespace.oid = pg_proc.pronamespace WHERE nspname = 'message_store' AND proname = 'write_message')").Scan(&alreadyInstalled); err != nil {
+		logrus.WithError(err).Error("Failure in postgres/install.go::Migrate")
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, createSchemaVersions); err != nil {
+		logrus.WithError(err).Error("Failure in postgres/install.go::Migrate")
+		return 0, err
+	}
+
+	var version int
+	if err := tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM message_store.schema_versions").Scan(&version); err != nil {
+		logrus.WithError(err).Error("Failure in postgres/install.go::Migrate")
+		return 0, err
+	}
+
+	adopting := version == 0 && alreadyInstalled
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+
+		if !adopting || m.version > baselineVersion {
+			logrus.WithField("version", m.version).Infof("migrating message store: %s", m.description)
+			for _, statement := range m.statements {
+				if _, err := tx.ExecContext(ctx, statement); err != nil {
+					logrus.WithError(err).WithField("version", m.version).Error("Failure in postgres/install.go::Migrate")
+					return 0, err
+				}
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, "INSERT INTO message_store.schema_versions (version, description) VALUES ($1, $2)", m.version, m.description); err != nil {
+			logrus.WithError(err).Error("Failure in postgres/install.go::Migrate")
+			return 0, err
+		}
+		version = m.version
+	}
+
+	if err := tx.Commit(); err != nil {
+		logrus.WithError(err).Error("Failure in postgres/install.go::Migrate")
+		return 0, err
+	}
+
+	return version, nil
+}
+
+//InstalledVersion reports which version of the message store schema a database is at, 0 when it has never been migrated
+func InstalledVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var table sql.NullString
+	if err := db.QueryRowContext(ctx, "SELECT to_regclass('message_store.schema_versions')::text").Scan(&table); err != nil {
+		logrus.WithError(err).Error("Failure in postgres/install.go::InstalledVersion")
+		return 0, err
+	}
+
+	if !table.Valid {
+		return 0, nil
+	}
+
+	var version int
+	if err := db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM message_store.schema_versions").Scan(&version); err != nil {
+		logrus.WithError(err).Error("Failure in postgres/install.go::InstalledVersion")
+		return 0, err
+	}
+
+	return version, nil
+}
@@ -0,0 +1,125 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	. "github.com/blackhatbrigade/gomessagestore/repository/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+// migrationStatements is the start of each statement run by migrations after the baseline, which is adopted as it is in these tests
+var migrationStatements = map[int][]string{
+	2: {"CREATE OR REPLACE FUNCTION message_store.get_last_message"},
+}
+
+func TestPostgresMigrate(t *testing.T) {
+	tests := []struct {
+		name             string
+		alreadyInstalled bool
+		installedVersion int
+		lockError        error
+		expectedVersion  int
+		expectedErr      error
+	}{{
+		name:             "when the database is up to date, nothing is run",
+		alreadyInstalled: true,
+		installedVersion: LatestSchemaVersion,
+		expectedVersion:  LatestSchemaVersion,
+	}, {
+		name:             "when the database has the message store but was never migrated, the baseline is adopted and the rest are run",
+		alreadyInstalled: true,
+		expectedVersion:  LatestSchemaVersion,
+	}, {
+		name:             "when the database was migrated to the baseline, the rest are run",
+		alreadyInstalled: true,
+		installedVersion: 1,
+		expectedVersion:  LatestSchemaVersion,
+	}, {
+		name:        "when the migration lock can't be taken, the error is returned",
+		lockError:   errors.New("bad things with db happened"),
+		expectedErr: errors.New("bad things with db happened"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+
+			mockDb.ExpectBegin()
+			lock := mockDb.ExpectExec("SELECT pg_advisory_xact_lock\\(\\$1\\)")
+			if test.lockError != nil {
+				lock.WillReturnError(test.lockError)
+				mockDb.ExpectRollback()
+			} else {
+				lock.WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDb.
+					ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM pg_proc JOIN pg_namespace ON pg_namespace.oid = pg_proc.pronamespace WHERE nspname = 'message_store' AND proname = 'write_message'\\)").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(test.alreadyInstalled))
+				mockDb.
+					ExpectExec("CREATE TABLE IF NOT EXISTS message_store.schema_versions").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDb.
+					ExpectQuery("SELECT COALESCE\\(MAX\\(version\\), 0\\) FROM message_store.schema_versions").
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(test.installedVersion))
+				for version := test.installedVersion + 1; version <= LatestSchemaVersion; version++ {
+					for _, statement := range migrationStatements[version] {
+						mockDb.ExpectExec(statement).WillReturnResult(sqlmock.NewResult(0, 0))
+					}
+					mockDb.
+						ExpectExec("INSERT INTO message_store.schema_versions").
+						WithArgs(version, sqlmock.AnyArg()).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+				mockDb.ExpectCommit()
+			}
+
+			version, err := Migrate(context.Background(), db)
+
+			assert.Equal(test.expectedVersion, version)
+			assert.Equal(test.expectedErr, err)
+			assert.Nil(mockDb.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostgresInstalledVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		table           interface{}
+		version         int
+		expectedVersion int
+	}{{
+		name:            "when the database was never migrated, the version is 0",
+		expectedVersion: 0,
+	}, {
+		name:            "when the database was migrated, the version is returned",
+		table:           "message_store.schema_versions",
+		version:         2,
+		expectedVersion: 2,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+
+			mockDb.
+				ExpectQuery("SELECT to_regclass\\('message_store.schema_versions'\\)::text").
+				WillReturnRows(sqlmock.NewRows([]string{"to_regclass"}).AddRow(test.table))
+			if test.table != nil {
+				mockDb.
+					ExpectQuery("SELECT COALESCE\\(MAX\\(version\\), 0\\) FROM message_store.schema_versions").
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(test.version))
+			}
+
+			version, err := InstalledVersion(context.Background(), db)
+
+			assert.Equal(test.expectedVersion, version)
+			assert.Nil(err)
+			assert.Nil(mockDb.ExpectationsWereMet())
+		})
+	}
+}
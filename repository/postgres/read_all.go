@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+func (r postgresRepo) GetAllMessagesSince(ctx context.Context, globalPosition int64, batchSize int, filter repository.CategoryFilter, types ...string) ([]*repository.MessageEnvelope, error) {
+	if batchSize < 0 {
+		logrus.WithError(repository.ErrNegativeBatchSize).Error("Failure in repo_postgres.go::GetAllMessagesSince")
+
+		return nil, repository.ErrNegativeBatchSize
+	}
+
+	if len(filter.Include) > 0 && len(includedCategories(filter)) == 0 {
+		logrus.Debug("every category included is also excluded")
+		return []*repository.MessageEnvelope{}, nil
+	}
+
+	// our return channel for our goroutine that will either finish or be cancelled
+	retChan := make(chan returnPair, 1)
+	go func() {
+		// last thing we do is ensure our return channel is populated
+		defer func() {
+			retChan <- returnPair{nil, nil}
+		}()
+
+		// the message store has no function for reading every category, so this reads the messages table directly
+		var msgs []*repository.MessageEnvelope
+		query, args := allMessagesQuery(globalPosition, batchSize, filter, types)
+		logrus.WithFields(map[string]interface{}{
+			"query":  query,
+			"params": args,
+		}).Debug("Running query on DB")
+		if err := sqlx.SelectContext(ctx, r.dbx, &msgs, query, args...); err != nil {
+			logrus.WithError(err).Error("Failure in repo_postgres.go::GetAllMessagesSince")
+			retChan <- returnPair{nil, err}
+			return
+		}
+
+		if len(msgs) == 0 {
+			logrus.Debug("read nothing from every category")
+			retChan <- returnPair{[]*repository.MessageEnvelope{}, nil}
+			return
+		}
+
+		retChan <- returnPair{msgs, nil}
+	}()
+
+	// wait for our return channel or the context to cancel
+	select {
+	case retval := <-retChan:
+		return retval.messages, retval.err
+	case <-ctx.Done():
+		return []*repository.MessageEnvelope{}, nil
+	}
+}
+
+// messageColumns are the columns of the messages table read into a MessageEnvelope
+const messageColumns = "id, stream_name, type, position, global_position, data, metadata, time"
+
+// allMessagesQuery builds the query for reading every category, with a placeholder for each category in the filter and each type.
+// The messages table is only indexed by category, so when categories are included each is read through the index on its own,
+// up to the batch size, and the reads are merged; excluded categories are simply never read. Checking every message's category
+// instead would scan the whole table.
+func allMessagesQuery(globalPosition int64, batchSize int, filter repository.CategoryFilter, types []string) (string, []interface{}) {
+	args := []interface{}{globalPosition, limit(batchSize)}
+
+	placeholders := func(categories []string) []string {
+		list := make([]string, len(categories))
+		for i, category := range categories {
+			args = append(args, category)
+			list[i] = fmt.Sprintf("$%d", len(args))
+		}
+
+		return list
+	}
+
+	if len(filter.Include) > 0 {
+		included := placeholders(includedCategories(filter))
+		condition, args := ofTypes(types, args)
+
+		reads := make([]string, len(included))
+		for i, category := range included {
+			reads[i] = "(SELECT " + messageColumns + " FROM messages WHERE category(stream_name) = " + category + " AND global_position >= $1" + condition + " ORDER BY global_position ASC LIMIT $2)"
+		}
+
+		return "SELECT " + messageColumns + " FROM (" + strings.Join(reads, " UNION ALL ") + ") AS included ORDER BY global_position ASC LIMIT $2", args
+	}
+
+	query := "SELECT " + messageColumns + " FROM messages WHERE global_position >= $1"
+	if len(filter.Exclude) > 0 {
+		query += " AND category(stream_name) NOT IN (" + strings.Join(placeholders(filter.Exclude), ", ") + ")"
+	}
+
+	condition, args := ofTypes(types, args)
+
+	return query + condition + " ORDER BY global_position ASC LIMIT $2", args
+}
+
+// includedCategories are the categories a filter includes that it doesn't also exclude
+func includedCategories(filter repository.CategoryFilter) []string {
+	included := make([]string, 0, len(filter.Include))
+	for _, category := range filter.Include {
+		if filter.Matches(category) {
+			included = append(included, category)
+		}
+	}
+
+	return included
+}
+
+// ofTypes returns the condition that limits a query on the messages table to messages of the types, adding them to the arguments;
+// no types reads every type. The message store's read functions can only do this through their SQL condition, which has to be
+// turned on with the message_store.sql_condition setting, so reads of some types query the messages table directly instead.
+func ofTypes(types []string, args []interface{}) (string, []interface{}) {
+	if len(types) == 0 {
+		return "", args
+	}
+
+	list := ""
+	for i, t := range types {
+		args = append(args, t)
+		if i > 0 {
+			list += ", "
+		}
+		list += fmt.Sprintf("$%d", len(args))
+	}
+
+	return " AND type IN (" + list + ")", args
+}
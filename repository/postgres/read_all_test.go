@@ -0,0 +1,114 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	. "github.com/blackhatbrigade/gomessagestore/repository/postgres"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRepoGetAllMessagesSince(t *testing.T) {
+	tests := []struct {
+		name             string
+		dbError          error
+		filter           repository.CategoryFilter
+		types            []string
+		expectedQuery    string
+		expectedArgs     []interface{}
+		expectedMessages []*repository.MessageEnvelope
+		expectedErr      error
+		batchSize        int
+	}{{
+		name:             "when there is no filter every category is read",
+		expectedQuery:    "SELECT .* FROM messages WHERE global_position >= \\$1 ORDER BY global_position ASC LIMIT \\$2",
+		expectedArgs:     []interface{}{int64(3), 1000},
+		expectedMessages: mockMessages,
+		batchSize:        1000,
+	}, {
+		name:             "when categories are included each is read through the category index and the reads are merged",
+		filter:           repository.CategoryFilter{Include: []string{"some_type", "some_other_type"}},
+		types:            []string{"Created"},
+		expectedQuery:    "SELECT .* FROM \\(\\(SELECT .* FROM messages WHERE category\\(stream_name\\) = \\$3 AND global_position >= \\$1 AND type IN \\(\\$5\\) ORDER BY global_position ASC LIMIT \\$2\\) UNION ALL \\(SELECT .* FROM messages WHERE category\\(stream_name\\) = \\$4 AND global_position >= \\$1 AND type IN \\(\\$5\\) ORDER BY global_position ASC LIMIT \\$2\\)\\) AS included ORDER BY global_position ASC LIMIT \\$2",
+		expectedArgs:     []interface{}{int64(3), 10, "some_type", "some_other_type", "Created"},
+		expectedMessages: mockMessages,
+		batchSize:        10,
+	}, {
+		name:             "when categories are included and excluded the excluded ones are never read",
+		filter:           repository.CategoryFilter{Include: []string{"some_type", "audit"}, Exclude: []string{"audit"}},
+		expectedQuery:    "SELECT .* FROM \\(\\(SELECT .* FROM messages WHERE category\\(stream_name\\) = \\$3 AND global_position >= \\$1 ORDER BY global_position ASC LIMIT \\$2\\)\\) AS included ORDER BY global_position ASC LIMIT \\$2",
+		expectedArgs:     []interface{}{int64(3), 10, "some_type"},
+		expectedMessages: mockMessages,
+		batchSize:        10,
+	}, {
+		name:             "when every category included is excluded nothing is read",
+		filter:           repository.CategoryFilter{Include: []string{"audit"}, Exclude: []string{"audit"}},
+		expectedMessages: []*repository.MessageEnvelope{},
+		batchSize:        10,
+	}, {
+		name:             "when types are given each gets a placeholder after the categories",
+		filter:           repository.CategoryFilter{Exclude: []string{"audit"}},
+		types:            []string{"Created", "Updated"},
+		expectedQuery:    "SELECT .* FROM messages WHERE global_position >= \\$1 AND category\\(stream_name\\) NOT IN \\(\\$3\\) AND type IN \\(\\$4, \\$5\\) ORDER BY global_position ASC LIMIT \\$2",
+		expectedArgs:     []interface{}{int64(3), 10, "audit", "Created", "Updated"},
+		expectedMessages: mockMessages,
+		batchSize:        10,
+	}, {
+		name:        "when asking for messages with a negative batch size, an error is returned",
+		expectedErr: repository.ErrNegativeBatchSize,
+		batchSize:   -10,
+	}, {
+		name:          "when there is an issue getting the messages an error should be returned",
+		expectedQuery: "SELECT .* FROM messages WHERE global_position >= \\$1 ORDER BY global_position ASC LIMIT \\$2",
+		expectedArgs:  []interface{}{int64(3), 1000},
+		dbError:       errors.New("bad things with db happened"),
+		expectedErr:   errors.New("bad things with db happened"),
+		batchSize:     1000,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			repo := NewPostgresRepository(db, logrus.New())
+
+			if test.expectedQuery != "" {
+				args := make([]driver.Value, len(test.expectedArgs))
+				for i, arg := range test.expectedArgs {
+					args[i] = arg
+				}
+
+				expectedQuery := mockDb.
+					ExpectQuery(test.expectedQuery).
+					WithArgs(args...)
+
+				if test.dbError == nil {
+					rows := sqlmock.NewRows([]string{"id", "stream_name", "type", "position", "global_position", "data", "metadata", "time"})
+					for _, row := range test.expectedMessages {
+						rows.AddRow(row.ID, row.StreamName, row.MessageType, row.Version, row.GlobalPosition, row.Data, row.Metadata, row.Time)
+					}
+					expectedQuery.WillReturnRows(rows)
+				} else {
+					expectedQuery.WillReturnError(test.dbError)
+				}
+			}
+
+			messages, err := repo.GetAllMessagesSince(context.Background(), 3, test.batchSize, test.filter, test.types...)
+
+			assert.Equal(test.expectedErr, err)
+			if test.expectedErr == nil {
+				assert.Len(messages, len(test.expectedMessages))
+				for i, msg := range messages {
+					assert.Equal(test.expectedMessages[i].ID, msg.ID)
+					assert.Equal(test.expectedMessages[i].GlobalPosition, msg.GlobalPosition)
+				}
+			}
+			assert.Nil(mockDb.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+// the message store has no functions for reading backward, so these read the messages table directly, through the same indexes its functions use
+
+func (r postgresRepo) GetAllMessagesInStreamBackward(ctx context.Context, streamName string, version int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+	if streamName == "" {
+		logrus.WithError(repository.ErrInvalidStreamName).Error("Failure in repo_postgres.go::GetAllMessagesInStreamBackward")
+
+		return nil, repository.ErrInvalidStreamName
+	}
+	if batchSize < 0 {
+		logrus.WithError(repository.ErrNegativeBatchSize).Error("Failure in repo_postgres.go::GetAllMessagesInStreamBackward")
+
+		return nil, repository.ErrNegativeBatchSize
+	}
+
+	query := "SELECT id, stream_name, type, position, global_position, data, metadata, time FROM messages WHERE stream_name = $1 AND position <= $2 ORDER BY position DESC LIMIT $3"
+	return r.readMessages(ctx, "GetAllMessagesInStreamBackward", query, streamName, version, limit(batchSize))
+}
+
+func (r postgresRepo) GetAllMessagesInCategoryBackward(ctx context.Context, category string, globalPosition int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+	if category == "" {
+		logrus.WithError(repository.ErrBlankCategory).Error("Failure in repo_postgres.go::GetAllMessagesInCategoryBackward")
+
+		return nil, repository.ErrBlankCategory
+	}
+	if batchSize < 0 {
+		logrus.WithError(repository.ErrNegativeBatchSize).Error("Failure in repo_postgres.go::GetAllMessagesInCategoryBackward")
+
+		return nil, repository.ErrNegativeBatchSize
+	}
+	if strings.Contains(category, "-") {
+		logrus.WithError(repository.ErrInvalidCategory).Error("Failure in repo_postgres.go::GetAllMessagesInCategoryBackward")
+		return nil, repository.ErrInvalidCategory
+	}
+
+	query := "SELECT id, stream_name, type, position, global_position, data, metadata, time FROM messages WHERE category(stream_name) = $1 AND global_position <= $2 ORDER BY global_position DESC LIMIT $3"
+	return r.readMessages(ctx, "GetAllMessagesInCategoryBackward", query, category, globalPosition, limit(batchSize))
+}
+
+// readMessages runs a query that reads the messages table directly, logging failures as the named method
+func (r postgresRepo) readMessages(ctx context.Context, name, query string, args ...interface{}) ([]*repository.MessageEnvelope, error) {
+	// our return channel for our goroutine that will either finish or be cancelled
+	retChan := make(chan returnPair, 1)
+	go func() {
+		// last thing we do is ensure our return channel is populated
+		defer func() {
+			retChan <- returnPair{nil, nil}
+		}()
+
+		var msgs []*repository.MessageEnvelope
+		logrus.WithFields(map[string]interface{}{
+			"query":  query,
+			"params": args,
+		}).Debug("Running query on DB")
+		if err := sqlx.SelectContext(ctx, r.dbx, &msgs, query, args...); err != nil {
+			logrus.WithError(err).Error("Failure in repo_postgres.go::" + name)
+			retChan <- returnPair{nil, err}
+			return
+		}
+
+		if len(msgs) == 0 {
+			logrus.Debugf("read nothing in %s", name)
+			retChan <- returnPair{[]*repository.MessageEnvelope{}, nil}
+			return
+		}
+
+		retChan <- returnPair{msgs, nil}
+	}()
+
+	// wait for our return channel or the context to cancel
+	select {
+	case retval := <-retChan:
+		return retval.messages, retval.err
+	case <-ctx.Done():
+		return []*repository.MessageEnvelope{}, nil
+	}
+}
@@ -0,0 +1,107 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	. "github.com/blackhatbrigade/gomessagestore/repository/postgres"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRepoReadBackward(t *testing.T) {
+	streamQuery := "SELECT .* FROM messages WHERE stream_name = \\$1 AND position <= \\$2 ORDER BY position DESC LIMIT \\$3"
+	categoryQuery := "SELECT .* FROM messages WHERE category\\(stream_name\\) = \\$1 AND global_position <= \\$2 ORDER BY global_position DESC LIMIT \\$3"
+
+	tests := []struct {
+		name          string
+		category      bool
+		target        string
+		dbError       error
+		expectedQuery string
+		expectedErr   error
+		batchSize     int
+	}{{
+		name:          "reading a stream backward reads the messages table newest first",
+		target:        "some_type-1",
+		expectedQuery: streamQuery,
+		batchSize:     10,
+	}, {
+		name:          "reading a category backward reads the messages table newest first",
+		category:      true,
+		target:        "some_type",
+		expectedQuery: categoryQuery,
+		batchSize:     10,
+	}, {
+		name:        "when the stream name is blank, an error is returned",
+		expectedErr: repository.ErrInvalidStreamName,
+		batchSize:   10,
+	}, {
+		name:        "when the category is blank, an error is returned",
+		category:    true,
+		expectedErr: repository.ErrBlankCategory,
+		batchSize:   10,
+	}, {
+		name:        "when the category has a hyphen, an error is returned",
+		category:    true,
+		target:      "some_type-1",
+		expectedErr: repository.ErrInvalidCategory,
+		batchSize:   10,
+	}, {
+		name:        "when asking for messages with a negative batch size, an error is returned",
+		target:      "some_type-1",
+		expectedErr: repository.ErrNegativeBatchSize,
+		batchSize:   -10,
+	}, {
+		name:          "when there is an issue getting the messages an error should be returned",
+		target:        "some_type-1",
+		expectedQuery: streamQuery,
+		dbError:       errors.New("bad things with db happened"),
+		expectedErr:   errors.New("bad things with db happened"),
+		batchSize:     10,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			repo := NewPostgresRepository(db, logrus.New())
+
+			newestFirst := []*repository.MessageEnvelope{mockMessages[1], mockMessages[0]}
+			if test.expectedQuery != "" {
+				expectedQuery := mockDb.
+					ExpectQuery(test.expectedQuery).
+					WithArgs(test.target, int64(7), test.batchSize)
+
+				if test.dbError == nil {
+					rows := sqlmock.NewRows([]string{"id", "stream_name", "type", "position", "global_position", "data", "metadata", "time"})
+					for _, row := range newestFirst {
+						rows.AddRow(row.ID, row.StreamName, row.MessageType, row.Version, row.GlobalPosition, row.Data, row.Metadata, row.Time)
+					}
+					expectedQuery.WillReturnRows(rows)
+				} else {
+					expectedQuery.WillReturnError(test.dbError)
+				}
+			}
+
+			var messages []*repository.MessageEnvelope
+			var err error
+			if test.category {
+				messages, err = repo.GetAllMessagesInCategoryBackward(context.Background(), test.target, 7, test.batchSize)
+			} else {
+				messages, err = repo.GetAllMessagesInStreamBackward(context.Background(), test.target, 7, test.batchSize)
+			}
+
+			assert.Equal(test.expectedErr, err)
+			if test.expectedErr == nil && assert.Len(messages, len(newestFirst)) {
+				for i, msg := range messages {
+					assert.Equal(newestFirst[i].ID, msg.ID)
+				}
+			}
+			assert.Nil(mockDb.ExpectationsWereMet())
+		})
+	}
+}
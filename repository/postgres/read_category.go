@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/jmoiron/sqlx"
 	"github.com/sirupsen/logrus"
 )
 
@@ -13,7 +14,7 @@ func (r postgresRepo) GetAllMessagesInCategory(ctx context.Context, category str
 	return r.GetAllMessagesInCategorySince(ctx, category, 0, batchSize)
 }
 
-func (r postgresRepo) GetAllMessagesInCategorySince(ctx context.Context, category string, globalPosition int64, batchSize int) (m []*repository.MessageEnvelope, err error) {
+func (r postgresRepo) GetAllMessagesInCategorySince(ctx context.Context, category string, globalPosition int64, batchSize int, types ...string) (m []*repository.MessageEnvelope, err error) {
 	if category == "" {
 		logrus.WithError(repository.ErrBlankCategory).Error("Failure in repo_postgres.go::GetAllMessagesInCategorySince")
 
@@ -28,6 +29,11 @@ func (r postgresRepo) GetAllMessagesInCategorySince(ctx context.Context, categor
 		logrus.WithError(repository.ErrInvalidCategory).Error("Failure in repo_postgres.go::GetAllMessagesInCategorySince")
 		return nil, repository.ErrInvalidCategory
 	}
+	if len(types) > 0 {
+		condition, args := ofTypes(types, []interface{}{category, globalPosition, limit(batchSize)})
+		query := "SELECT id, stream_name, type, position, global_position, data, metadata, time FROM messages WHERE category(stream_name) = $1 AND global_position >= $2" + condition + " ORDER BY global_position ASC LIMIT $3"
+		return r.readMessages(ctx, "GetAllMessagesInCategorySince", query, args...)
+	}
 
 	// our return channel for our goroutine that will either finish or be cancelled
 	retChan := make(chan returnPair, 1)
@@ -51,10 +57,10 @@ func (r postgresRepo) GetAllMessagesInCategorySince(ctx context.Context, categor
 			"params": []string{
 				category,
 				fmt.Sprintf("%d", globalPosition),
-				fmt.Sprintf("%d", batchSize),
+				fmt.Sprintf("%d", functionBatchSize(batchSize)),
 			},
 		}).Debug("Running query on DB")
-		if err := r.dbx.SelectContext(ctx, &msgs, query, category, globalPosition, batchSize); err != nil {
+		if err := sqlx.SelectContext(ctx, r.dbx, &msgs, query, category, globalPosition, functionBatchSize(batchSize)); err != nil {
 			logrus.WithError(err).Error("Failure in repo_postgres.go::GetAllMessagesInCategorySince")
 			retChan <- returnPair{nil, err}
 			return
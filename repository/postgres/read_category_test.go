@@ -222,6 +222,7 @@ func TestPostgresRepoFindAllMessagesInCategorySince(t *testing.T) {
 			logrusLogger := logrus.New()
 			repo := NewPostgresRepository(db, logrusLogger)
 			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
 
 			expectedQuery := mockDb.
 				ExpectQuery("SELECT \\* FROM get_category_messages\\(\\$1, \\$2, \\$3\\)").
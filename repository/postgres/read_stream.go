@@ -2,9 +2,11 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 
 	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/jmoiron/sqlx"
 	"github.com/sirupsen/logrus"
 )
 
@@ -38,7 +40,7 @@ func (r postgresRepo) GetLastMessageInStream(ctx context.Context, streamName str
 				streamName,
 			},
 		}).Debug("Running query on DB")
-		if err := r.dbx.SelectContext(ctx, &msgs, query, streamName); err != nil {
+		if err := sqlx.SelectContext(ctx, r.dbx, &msgs, query, streamName); err != nil {
 			logrus.WithError(err).Error("Failure in repo_postgres.go::GetLastMessageInStream")
 			retChan <- returnPair{nil, err}
 			return
@@ -70,7 +72,7 @@ func (r postgresRepo) GetLastMessageInStream(ctx context.Context, streamName str
 	}
 }
 
-func (r postgresRepo) GetAllMessagesInStreamSince(ctx context.Context, streamName string, globalPosition int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+func (r postgresRepo) GetAllMessagesInStreamSince(ctx context.Context, streamName string, globalPosition int64, batchSize int, types ...string) ([]*repository.MessageEnvelope, error) {
 	if streamName == "" {
 		logrus.WithError(repository.ErrInvalidStreamName).Error("Failure in repo_postgres.go::GetAllMessagesInStreamSince")
 
@@ -81,6 +83,11 @@ func (r postgresRepo) GetAllMessagesInStreamSince(ctx context.Context, streamNam
 
 		return nil, repository.ErrNegativeBatchSize
 	}
+	if len(types) > 0 {
+		condition, args := ofTypes(types, []interface{}{streamName, globalPosition, limit(batchSize)})
+		query := "SELECT id, stream_name, type, position, global_position, data, metadata, time FROM messages WHERE stream_name = $1 AND position >= $2" + condition + " ORDER BY position ASC LIMIT $3"
+		return r.readMessages(ctx, "GetAllMessagesInStreamSince", query, args...)
+	}
 
 	// our return channel for our goroutine that will either finish or be cancelled
 	retChan := make(chan returnPair, 1)
@@ -97,15 +104,16 @@ func (r postgresRepo) GetAllMessagesInStreamSince(ctx context.Context, streamNam
 		  _batch_size bigint DEFAULT 1000,
 		  _condition varchar DEFAULT NULL
 		)*/
-		query := "SELECT * FROM get_stream_messages($1, $2)"
+		query := "SELECT * FROM get_stream_messages($1, $2, $3)"
 		logrus.WithFields(map[string]interface{}{
 			"query": query,
 			"params": []string{
 				streamName,
 				fmt.Sprintf("%d", globalPosition),
+				fmt.Sprintf("%d", functionBatchSize(batchSize)),
 			},
 		}).Debug("Running query on DB")
-		if err := r.dbx.SelectContext(ctx, &msgs, query, streamName, globalPosition); err != nil {
+		if err := sqlx.SelectContext(ctx, r.dbx, &msgs, query, streamName, globalPosition, functionBatchSize(batchSize)); err != nil {
 			logrus.WithError(err).Error("Failure in repo_postgres.go::GetAllMessagesInStreamSince")
 			retChan <- returnPair{nil, err}
 			return
@@ -132,3 +140,52 @@ func (r postgresRepo) GetAllMessagesInStreamSince(ctx context.Context, streamNam
 		return []*repository.MessageEnvelope{}, nil
 	}
 }
+
+func (r postgresRepo) GetStreamVersion(ctx context.Context, streamName string) (int64, error) {
+	if streamName == "" {
+		logrus.WithError(repository.ErrInvalidStreamName).Error("Failure in repo_postgres.go::GetStreamVersion")
+
+		return 0, repository.ErrInvalidStreamName
+	}
+
+	type versionPair struct {
+		version int64
+		err     error
+	}
+
+	// our return channel for our goroutine that will either finish or be cancelled
+	retChan := make(chan versionPair, 1)
+	go func() {
+		/*stream_version(
+		  _stream_name varchar
+		)*/
+		var version sql.NullInt64
+		query := "SELECT stream_version($1)"
+		logrus.WithFields(map[string]interface{}{
+			"query": query,
+			"params": []string{
+				streamName,
+			},
+		}).Debug("Running query on DB")
+		if err := r.dbx.QueryRowxContext(ctx, query, streamName).Scan(&version); err != nil {
+			logrus.WithError(err).Error("Failure in repo_postgres.go::GetStreamVersion")
+			retChan <- versionPair{0, err}
+			return
+		}
+
+		if !version.Valid { // stream_version() is NULL for a stream with no messages
+			retChan <- versionPair{-1, nil}
+			return
+		}
+
+		retChan <- versionPair{version.Int64, nil}
+	}()
+
+	// wait for our return channel or the context to cancel
+	select {
+	case retval := <-retChan:
+		return retval.version, retval.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
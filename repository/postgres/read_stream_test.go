@@ -78,10 +78,11 @@ func TestPostgresRepoFindAllMessagesInStream(t *testing.T) {
 			logrusLogger := logrus.New()
 			repo := NewPostgresRepository(db, logrusLogger)
 			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
 
 			expectedQuery := mockDb.
-				ExpectQuery("SELECT \\* FROM get_stream_messages\\(\\$1, \\$2\\)").
-				WithArgs(test.streamName, 0).
+				ExpectQuery("SELECT \\* FROM get_stream_messages\\(\\$1, \\$2, \\$3\\)").
+				WithArgs(test.streamName, 0, test.batchSize).
 				WillDelayFor(time.Millisecond * 10)
 
 			addedMessage := -1
@@ -201,10 +202,11 @@ func TestPostgresRepoFindAllMessagesInStreamSince(t *testing.T) {
 			logrusLogger := logrus.New()
 			repo := NewPostgresRepository(db, logrusLogger)
 			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
 
 			expectedQuery := mockDb.
-				ExpectQuery("SELECT \\* FROM get_stream_messages\\(\\$1, \\$2\\)").
-				WithArgs(test.streamName, test.position).
+				ExpectQuery("SELECT \\* FROM get_stream_messages\\(\\$1, \\$2, \\$3\\)").
+				WithArgs(test.streamName, test.position, test.batchSize).
 				WillDelayFor(time.Millisecond * 10)
 
 			addedMessage := -1
@@ -285,6 +287,7 @@ func TestPostgresRepoFindLastMessageInStream(t *testing.T) {
 			logrusLogger := logrus.New()
 			repo := NewPostgresRepository(db, logrusLogger)
 			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
 
 			expectedQuery := mockDb.
 				ExpectQuery("SELECT \\* FROM get_last_message\\(\\$1\\)").
@@ -323,3 +326,57 @@ func TestPostgresRepoFindLastMessageInStream(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgresRepoGetStreamVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		dbError         error
+		dbVersion       interface{}
+		expectedVersion int64
+		expectedErr     error
+		streamName      string
+	}{{
+		name:            "when the stream has messages it should return the version of the last one",
+		streamName:      "some_type-12345",
+		dbVersion:       int64(5),
+		expectedVersion: 5,
+	}, {
+		name:            "when the stream has no messages it should return -1",
+		streamName:      "some_type-12345",
+		dbVersion:       nil,
+		expectedVersion: -1,
+	}, {
+		name:        "when asking for the version of a stream with a blank Name, an error is returned",
+		expectedErr: repository.ErrInvalidStreamName,
+	}, {
+		name:        "when there is an issue getting the version an error should be returned",
+		streamName:  "some_type-12345",
+		dbError:     errors.New("bad things with db happened"),
+		expectedErr: errors.New("bad things with db happened"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			logrusLogger := logrus.New()
+			repo := NewPostgresRepository(db, logrusLogger)
+			ctx := context.Background()
+
+			expectedQuery := mockDb.
+				ExpectQuery("SELECT stream_version\\(\\$1\\)").
+				WithArgs(test.streamName)
+
+			if test.dbError == nil {
+				expectedQuery.WillReturnRows(sqlmock.NewRows([]string{"stream_version"}).AddRow(test.dbVersion))
+			} else {
+				expectedQuery.WillReturnError(test.dbError)
+			}
+
+			version, err := repo.GetStreamVersion(ctx, test.streamName)
+
+			assert.Equal(test.expectedVersion, version)
+			assert.Equal(test.expectedErr, err)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// atTime turns a time into the UTC timestamp the message store keeps times as, whatever the session's time zone is
+const atTime = "($2::timestamptz AT TIME ZONE 'UTC')"
+
+type positionPair struct {
+	position int64
+	err      error
+}
+
+func (r postgresRepo) GetStreamVersionAtTime(ctx context.Context, streamName string, t time.Time) (int64, error) {
+	if streamName == "" {
+		logrus.WithError(repository.ErrInvalidStreamName).Error("Failure in repo_postgres.go::GetStreamVersionAtTime")
+
+		return 0, repository.ErrInvalidStreamName
+	}
+
+	// our return channel for our goroutine that will either finish or be cancelled
+	retChan := make(chan positionPair, 1)
+	go func() {
+		// streams are read through their index, so only the stream's own messages are looked at
+		var version int64
+		query := "SELECT COALESCE(" +
+			"(SELECT MIN(position) FROM messages WHERE stream_name = $1 AND time >= " + atTime + "), " +
+			"(SELECT COALESCE(MAX(position), -1) + 1 FROM messages WHERE stream_name = $1))"
+		logrus.WithFields(map[string]interface{}{
+			"query":  query,
+			"params": []interface{}{streamName, t},
+		}).Debug("Running query on DB")
+		if err := r.dbx.QueryRowxContext(ctx, query, streamName, t).Scan(&version); err != nil {
+			logrus.WithError(err).Error("Failure in repo_postgres.go::GetStreamVersionAtTime")
+			retChan <- positionPair{0, err}
+			return
+		}
+
+		retChan <- positionPair{version, nil}
+	}()
+
+	// wait for our return channel or the context to cancel
+	select {
+	case retval := <-retChan:
+		return retval.position, retval.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// GetGlobalPositionAtTime binary searches the messages by global position, as time isn't indexed. Messages are stamped with the time
+// they're written, so times go up with global positions, give or take transactions committing out of order around t.
+func (r postgresRepo) GetGlobalPositionAtTime(ctx context.Context, t time.Time) (int64, error) {
+	// our return channel for our goroutine that will either finish or be cancelled
+	retChan := make(chan positionPair, 1)
+	go func() {
+		position, err := r.searchGlobalPosition(ctx, t)
+		if err != nil {
+			logrus.WithError(err).Error("Failure in repo_postgres.go::GetGlobalPositionAtTime")
+		}
+		retChan <- positionPair{position, err}
+	}()
+
+	// wait for our return channel or the context to cancel
+	select {
+	case retval := <-retChan:
+		return retval.position, retval.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// searchGlobalPosition finds the first global position written at or after t, or the next global position when there is none
+func (r postgresRepo) searchGlobalPosition(ctx context.Context, t time.Time) (int64, error) {
+	var low, high int64
+	query := "SELECT COALESCE(MIN(global_position), 1), COALESCE(MAX(global_position), 0) + 1 FROM messages"
+	logrus.WithField("query", query).Debug("Running query on DB")
+	if err := r.dbx.QueryRowxContext(ctx, query).Scan(&low, &high); err != nil {
+		return 0, err
+	}
+
+	// global positions have gaps, so each probe looks at the first message at or after the middle
+	found := high
+	query = "SELECT global_position, time >= " + atTime + " FROM messages WHERE global_position >= $1 ORDER BY global_position LIMIT 1"
+	for low < high {
+		middle := low + (high-low)/2
+
+		var position int64
+		var atOrAfter bool
+		logrus.WithFields(map[string]interface{}{
+			"query":  query,
+			"params": []interface{}{middle, t},
+		}).Debug("Running query on DB")
+		if err := r.dbx.QueryRowxContext(ctx, query, middle, t).Scan(&position, &atOrAfter); err != nil {
+			return 0, err
+		}
+
+		if atOrAfter {
+			found = position
+			high = middle
+		} else {
+			low = position + 1
+		}
+	}
+
+	return found, nil
+}
@@ -0,0 +1,150 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	. "github.com/blackhatbrigade/gomessagestore/repository/postgres"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRepoGetStreamVersionAtTime(t *testing.T) {
+	at := time.Date(2020, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		streamName      string
+		dbError         error
+		expectedVersion int64
+		expectedErr     error
+	}{{
+		name:            "the version comes straight from the query",
+		streamName:      "some_type-1",
+		expectedVersion: 4,
+	}, {
+		name:        "when the stream name is blank, an error is returned",
+		expectedErr: repository.ErrInvalidStreamName,
+	}, {
+		name:        "when there is an issue running the query an error should be returned",
+		streamName:  "some_type-1",
+		dbError:     errors.New("bad things with db happened"),
+		expectedErr: errors.New("bad things with db happened"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			repo := NewPostgresRepository(db, logrus.New())
+
+			if test.streamName != "" {
+				expectedQuery := mockDb.
+					ExpectQuery("SELECT COALESCE\\(\\(SELECT MIN\\(position\\) FROM messages WHERE stream_name = \\$1 AND time >= \\(\\$2::timestamptz AT TIME ZONE 'UTC'\\)\\), \\(SELECT COALESCE\\(MAX\\(position\\), -1\\) \\+ 1 FROM messages WHERE stream_name = \\$1\\)\\)").
+					WithArgs(test.streamName, at)
+				if test.dbError == nil {
+					expectedQuery.WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(test.expectedVersion))
+				} else {
+					expectedQuery.WillReturnError(test.dbError)
+				}
+			}
+
+			version, err := repo.GetStreamVersionAtTime(context.Background(), test.streamName, at)
+
+			assert.Equal(test.expectedErr, err)
+			assert.Equal(test.expectedVersion, version)
+			assert.Nil(mockDb.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostgresRepoGetGlobalPositionAtTime(t *testing.T) {
+	at := time.Date(2020, 3, 3, 0, 0, 0, 0, time.UTC)
+	boundsQuery := "SELECT COALESCE\\(MIN\\(global_position\\), 1\\), COALESCE\\(MAX\\(global_position\\), 0\\) \\+ 1 FROM messages"
+	probeQuery := "SELECT global_position, time >= \\(\\$2::timestamptz AT TIME ZONE 'UTC'\\) FROM messages WHERE global_position >= \\$1 ORDER BY global_position LIMIT 1"
+
+	type probe struct {
+		middle    int64
+		position  int64
+		atOrAfter bool
+	}
+
+	tests := []struct {
+		name             string
+		low, high        int64
+		probes           []probe
+		dbError          error
+		expectedPosition int64
+		expectedErr      error
+	}{{
+		name: "the search narrows down on the first message at or after the time",
+		low:  1,
+		high: 11,
+		probes: []probe{
+			{6, 6, false},
+			{9, 9, true},
+			{8, 8, true},
+			{7, 7, true},
+		},
+		expectedPosition: 7,
+	}, {
+		name: "gaps in global positions are stepped over",
+		low:  1,
+		high: 11,
+		probes: []probe{
+			{6, 9, true},
+			{3, 3, false},
+			{5, 9, true},
+			{4, 9, true},
+		},
+		expectedPosition: 9,
+	}, {
+		name: "when every message is older, the next global position is returned",
+		low:  1,
+		high: 3,
+		probes: []probe{
+			{2, 2, false},
+		},
+		expectedPosition: 3,
+	}, {
+		name:             "when there are no messages, the first global position is returned",
+		low:              1,
+		high:             1,
+		expectedPosition: 1,
+	}, {
+		name:        "when there is an issue running the query an error should be returned",
+		dbError:     errors.New("bad things with db happened"),
+		expectedErr: errors.New("bad things with db happened"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			repo := NewPostgresRepository(db, logrus.New())
+
+			bounds := mockDb.ExpectQuery(boundsQuery)
+			if test.dbError != nil {
+				bounds.WillReturnError(test.dbError)
+			} else {
+				bounds.WillReturnRows(sqlmock.NewRows([]string{"coalesce", "coalesce"}).AddRow(test.low, test.high))
+			}
+			for _, p := range test.probes {
+				mockDb.
+					ExpectQuery(probeQuery).
+					WithArgs(p.middle, at).
+					WillReturnRows(sqlmock.NewRows([]string{"global_position", "?column?"}).AddRow(p.position, p.atOrAfter))
+			}
+
+			position, err := repo.GetGlobalPositionAtTime(context.Background(), at)
+
+			assert.Equal(test.expectedErr, err)
+			assert.Equal(test.expectedPosition, position)
+			assert.Nil(mockDb.ExpectationsWereMet())
+		})
+	}
+}
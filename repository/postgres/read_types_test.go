@@ -0,0 +1,85 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	. "github.com/blackhatbrigade/gomessagestore/repository/postgres"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRepoReadTypes(t *testing.T) {
+	streamQuery := "SELECT .* FROM messages WHERE stream_name = \\$1 AND position >= \\$2 AND type IN \\(\\$4, \\$5\\) ORDER BY position ASC LIMIT \\$3"
+	categoryQuery := "SELECT .* FROM messages WHERE category\\(stream_name\\) = \\$1 AND global_position >= \\$2 AND type IN \\(\\$4, \\$5\\) ORDER BY global_position ASC LIMIT \\$3"
+
+	tests := []struct {
+		name          string
+		category      bool
+		target        string
+		dbError       error
+		expectedQuery string
+		expectedErr   error
+	}{{
+		name:          "reading a stream's types reads the messages table with a placeholder for each type",
+		target:        "some_type-1",
+		expectedQuery: streamQuery,
+	}, {
+		name:          "reading a category's types reads the messages table with a placeholder for each type",
+		category:      true,
+		target:        "some_type",
+		expectedQuery: categoryQuery,
+	}, {
+		name:        "when the category has a hyphen, an error is returned",
+		category:    true,
+		target:      "some_type-1",
+		expectedErr: repository.ErrInvalidCategory,
+	}, {
+		name:          "when there is an issue getting the messages an error should be returned",
+		target:        "some_type-1",
+		expectedQuery: streamQuery,
+		dbError:       errors.New("bad things with db happened"),
+		expectedErr:   errors.New("bad things with db happened"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			repo := NewPostgresRepository(db, logrus.New())
+
+			if test.expectedQuery != "" {
+				expectedQuery := mockDb.
+					ExpectQuery(test.expectedQuery).
+					WithArgs(test.target, int64(7), 10, "Created", "Updated")
+
+				if test.dbError == nil {
+					rows := sqlmock.NewRows([]string{"id", "stream_name", "type", "position", "global_position", "data", "metadata", "time"})
+					for _, row := range mockMessages {
+						rows.AddRow(row.ID, row.StreamName, row.MessageType, row.Version, row.GlobalPosition, row.Data, row.Metadata, row.Time)
+					}
+					expectedQuery.WillReturnRows(rows)
+				} else {
+					expectedQuery.WillReturnError(test.dbError)
+				}
+			}
+
+			var messages []*repository.MessageEnvelope
+			var err error
+			if test.category {
+				messages, err = repo.GetAllMessagesInCategorySince(context.Background(), test.target, 7, 10, "Created", "Updated")
+			} else {
+				messages, err = repo.GetAllMessagesInStreamSince(context.Background(), test.target, 7, 10, "Created", "Updated")
+			}
+
+			assert.Equal(test.expectedErr, err)
+			if test.expectedErr == nil {
+				assert.Len(messages, len(mockMessages))
+			}
+			assert.Nil(mockDb.ExpectationsWereMet())
+		})
+	}
+}
@@ -5,22 +5,54 @@ import (
 
 	"github.com/blackhatbrigade/gomessagestore/repository"
 	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
 	"github.com/sirupsen/logrus"
 )
 
 //NewPostgresRepository creates a new in memory implementation for the messagestore reop
-func NewPostgresRepository(db *sql.DB, log logrus.FieldLogger) repository.Repository {
+func NewPostgresRepository(db *sql.DB, log logrus.FieldLogger) repository.TxRepository {
+	return NewPostgresRepositoryWithExt(sqlx.NewDb(db, "postgres"), log)
+}
+
+//NewPostgresRepositoryWithExt creates a postgres repository that runs its queries through ext, which can be an *sqlx.DB or an *sqlx.Tx;
+//with a transaction, messages are only written when the caller commits it
+func NewPostgresRepositoryWithExt(ext sqlx.ExtContext, log logrus.FieldLogger) repository.TxRepository {
 	r := new(postgresRepo)
-	r.dbx = sqlx.NewDb(db, "postgres")
+	r.dbx = ext
+	r.log = log
 	return r
 }
 
 type postgresRepo struct {
-	dbx *sqlx.DB
-	log logrus.Logger
+	dbx sqlx.ExtContext
+	log logrus.FieldLogger
+}
+
+//WithTx returns a repository that reads and writes as part of the transaction
+func (r postgresRepo) WithTx(tx *sql.Tx) repository.Repository {
+	// sqlx can't wrap a transaction it didn't begin, but these queries don't need the driver name it leaves unset
+	return NewPostgresRepositoryWithExt(&sqlx.Tx{Tx: tx, Mapper: reflectx.NewMapperFunc("db", sqlx.NameMapper)}, r.log)
 }
 
 type returnPair struct {
 	messages []*repository.MessageEnvelope
 	err      error
 }
+
+// functionBatchSize is the batch size the Eventide functions read with, which take -1 rather than 0 to mean no limit
+func functionBatchSize(batchSize int) int {
+	if batchSize == 0 {
+		return -1
+	}
+
+	return batchSize
+}
+
+// limit is the argument for a LIMIT, which is NULL rather than 0 for no limit
+func limit(batchSize int) interface{} {
+	if batchSize == 0 {
+		return nil
+	}
+
+	return batchSize
+}
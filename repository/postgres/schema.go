@@ -0,0 +1,425 @@
+package postgres
+
+// migration is one step in building the message store schema; every statement is safe to run again
+type migration struct {
+	version     int
+	description string
+	statements  []string
+}
+
+// migrations builds the Eventide message store schema, in order. Never change one that's been released, add another.
+var migrations = []migration{{
+	version:     1,
+	description: "Eventide message store schema, messages table, indexes and functions",
+	statements: []string{
+		createMessagesTable,
+		createMessageType,
+		createHash64,
+		createCategory,
+		createID,
+		createCardinalID,
+		createIsCategory,
+		createIndexes,
+		createStreamVersion,
+		createAcquireLock,
+		createWriteMessage,
+		createGetStreamMessages,
+		createGetCategoryMessages,
+		createGetLastStreamMessage,
+	},
+}, {
+	version:     2,
+	description: "get_last_message, the name this package reads the last message in a stream with",
+	statements: []string{
+		createGetLastMessage,
+	},
+}}
+
+// createSchemaVersions keeps track of which migrations have been run
+const createSchemaVersions = `
+CREATE SCHEMA IF NOT EXISTS message_store;
+
+CREATE TABLE IF NOT EXISTS message_store.schema_versions (
+  version integer PRIMARY KEY,
+  description text NOT NULL,
+  installed_at timestamp WITHOUT TIME ZONE DEFAULT (now() AT TIME ZONE 'utc') NOT NULL
+);`
+
+const createMessagesTable = `
+CREATE TABLE IF NOT EXISTS message_store.messages (
+  global_position bigserial NOT NULL PRIMARY KEY,
+  position bigint NOT NULL,
+  time timestamp WITHOUT TIME ZONE DEFAULT (now() AT TIME ZONE 'utc') NOT NULL,
+  stream_name text NOT NULL,
+  type text NOT NULL,
+  data jsonb,
+  metadata jsonb,
+  id uuid NOT NULL
+);`
+
+const createMessageType = `
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1 FROM pg_type JOIN pg_namespace ON pg_namespace.oid = pg_type.typnamespace
+    WHERE pg_namespace.nspname = 'message_store' AND pg_type.typname = 'message'
+  ) THEN
+    CREATE TYPE message_store.message AS (
+      id varchar,
+      stream_name varchar,
+      type varchar,
+      position bigint,
+      global_position bigint,
+      data varchar,
+      metadata varchar,
+      time timestamp
+    );
+  END IF;
+END;
+$$;`
+
+const createHash64 = `
+CREATE OR REPLACE FUNCTION message_store.hash_64(
+  value varchar
+)
+RETURNS bigint
+AS $$
+DECLARE
+  _hash bigint;
+BEGIN
+  SELECT left('x' || md5(hash_64.value), 17)::bit(64)::bigint INTO _hash;
+  RETURN _hash;
+END;
+$$ LANGUAGE plpgsql
+IMMUTABLE;`
+
+const createCategory = `
+CREATE OR REPLACE FUNCTION message_store.category(
+  stream_name varchar
+)
+RETURNS varchar
+AS $$
+BEGIN
+  RETURN split_part(category.stream_name, '-', 1);
+END;
+$$ LANGUAGE plpgsql
+IMMUTABLE;`
+
+const createID = `
+CREATE OR REPLACE FUNCTION message_store.id(
+  stream_name varchar
+)
+RETURNS varchar
+AS $$
+DECLARE
+  _id_separator_position integer;
+BEGIN
+  _id_separator_position := strpos(id.stream_name, '-');
+
+  IF _id_separator_position = 0 THEN
+    RETURN NULL;
+  END IF;
+
+  RETURN substring(id.stream_name, _id_separator_position + 1);
+END;
+$$ LANGUAGE plpgsql
+IMMUTABLE;`
+
+const createCardinalID = `
+CREATE OR REPLACE FUNCTION message_store.cardinal_id(
+  stream_name varchar
+)
+RETURNS varchar
+AS $$
+DECLARE
+  _id varchar;
+BEGIN
+  _id := message_store.id(cardinal_id.stream_name);
+
+  IF _id IS NULL THEN
+    RETURN NULL;
+  END IF;
+
+  RETURN split_part(_id, '+', 1);
+END;
+$$ LANGUAGE plpgsql
+IMMUTABLE;`
+
+const createIsCategory = `
+CREATE OR REPLACE FUNCTION message_store.is_category(
+  stream_name varchar
+)
+RETURNS boolean
+AS $$
+BEGIN
+  RETURN strpos(is_category.stream_name, '-') = 0;
+END;
+$$ LANGUAGE plpgsql
+IMMUTABLE;`
+
+const createIndexes = `
+CREATE UNIQUE INDEX IF NOT EXISTS messages_id ON message_store.messages (
+  id
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS messages_stream ON message_store.messages (
+  stream_name,
+  position
+);
+
+CREATE INDEX IF NOT EXISTS messages_category ON message_store.messages (
+  message_store.category(stream_name),
+  global_position,
+  message_store.category(metadata->>'correlationStreamName')
+);`
+
+const createStreamVersion = `
+CREATE OR REPLACE FUNCTION message_store.stream_version(
+  stream_name varchar
+)
+RETURNS bigint
+AS $$
+DECLARE
+  _stream_version bigint;
+BEGIN
+  SELECT max(position) INTO _stream_version
+  FROM message_store.messages
+  WHERE messages.stream_name = stream_version.stream_name;
+
+  RETURN _stream_version;
+END;
+$$ LANGUAGE plpgsql
+VOLATILE;`
+
+const createAcquireLock = `
+CREATE OR REPLACE FUNCTION message_store.acquire_lock(
+  stream_name varchar
+)
+RETURNS bigint
+AS $$
+DECLARE
+  _category_name_hash bigint;
+BEGIN
+  _category_name_hash := message_store.hash_64(message_store.category(acquire_lock.stream_name));
+  PERFORM pg_advisory_xact_lock(_category_name_hash);
+
+  RETURN _category_name_hash;
+END;
+$$ LANGUAGE plpgsql
+VOLATILE;`
+
+const createWriteMessage = `
+CREATE OR REPLACE FUNCTION message_store.write_message(
+  id varchar,
+  stream_name varchar,
+  "type" varchar,
+  data jsonb,
+  metadata jsonb DEFAULT NULL,
+  expected_version bigint DEFAULT NULL
+)
+RETURNS bigint
+AS $$
+DECLARE
+  _stream_version bigint;
+  _next_position bigint;
+BEGIN
+  PERFORM message_store.acquire_lock(write_message.stream_name);
+
+  _stream_version := coalesce(message_store.stream_version(write_message.stream_name), -1);
+
+  IF write_message.expected_version IS NOT NULL AND write_message.expected_version != _stream_version THEN
+    RAISE EXCEPTION
+      'Wrong expected version: % (Stream: %, Stream Version: %)',
+      write_message.expected_version,
+      write_message.stream_name,
+      _stream_version;
+  END IF;
+
+  _next_position := _stream_version + 1;
+
+  INSERT INTO message_store.messages (id, stream_name, position, type, data, metadata)
+  VALUES (
+    write_message.id::uuid,
+    write_message.stream_name,
+    _next_position,
+    write_message.type,
+    write_message.data,
+    write_message.metadata
+  );
+
+  RETURN _next_position;
+END;
+$$ LANGUAGE plpgsql
+VOLATILE;`
+
+// createGetStreamMessages doesn't refuse category names the way Eventide's does, as command streams (category:command) have no ID
+const createGetStreamMessages = `
+CREATE OR REPLACE FUNCTION message_store.get_stream_messages(
+  stream_name varchar,
+  "position" bigint DEFAULT 0,
+  batch_size bigint DEFAULT 1000,
+  condition varchar DEFAULT NULL
+)
+RETURNS SETOF message_store.message
+AS $$
+DECLARE
+  _command text;
+BEGIN
+  _command := '
+    SELECT
+      id::varchar,
+      stream_name::varchar,
+      type::varchar,
+      position::bigint,
+      global_position::bigint,
+      data::varchar,
+      metadata::varchar,
+      time::timestamp
+    FROM message_store.messages
+    WHERE stream_name = $1 AND position >= $2';
+
+  IF get_stream_messages.condition IS NOT NULL THEN
+    IF coalesce(current_setting('message_store.sql_condition', true), 'off') = 'off' THEN
+      RAISE EXCEPTION 'Retrieval with SQL condition is not activated';
+    END IF;
+
+    _command := _command || ' AND (' || get_stream_messages.condition || ')';
+  END IF;
+
+  _command := _command || ' ORDER BY position ASC';
+
+  IF coalesce(get_stream_messages.batch_size, 1000) != -1 THEN
+    _command := _command || ' LIMIT $3';
+  END IF;
+
+  RETURN QUERY EXECUTE _command USING
+    get_stream_messages.stream_name,
+    coalesce(get_stream_messages.position, 0),
+    coalesce(get_stream_messages.batch_size, 1000);
+END;
+$$ LANGUAGE plpgsql
+VOLATILE;`
+
+const createGetCategoryMessages = `
+CREATE OR REPLACE FUNCTION message_store.get_category_messages(
+  category varchar,
+  "position" bigint DEFAULT 1,
+  batch_size bigint DEFAULT 1000,
+  correlation varchar DEFAULT NULL,
+  consumer_group_member bigint DEFAULT NULL,
+  consumer_group_size bigint DEFAULT NULL,
+  condition varchar DEFAULT NULL
+)
+RETURNS SETOF message_store.message
+AS $$
+DECLARE
+  _command text;
+BEGIN
+  IF NOT message_store.is_category(get_category_messages.category) THEN
+    RAISE EXCEPTION 'Must be a category: %', get_category_messages.category;
+  END IF;
+
+  _command := '
+    SELECT
+      id::varchar,
+      stream_name::varchar,
+      type::varchar,
+      position::bigint,
+      global_position::bigint,
+      data::varchar,
+      metadata::varchar,
+      time::timestamp
+    FROM message_store.messages
+    WHERE message_store.category(stream_name) = $1 AND global_position >= $2';
+
+  IF get_category_messages.correlation IS NOT NULL THEN
+    IF NOT message_store.is_category(get_category_messages.correlation) THEN
+      RAISE EXCEPTION 'Correlation must be a category (Correlation: %)', get_category_messages.correlation;
+    END IF;
+
+    _command := _command || ' AND message_store.category(metadata->>''correlationStreamName'') = $4';
+  END IF;
+
+  IF (get_category_messages.consumer_group_member IS NULL) != (get_category_messages.consumer_group_size IS NULL) THEN
+    RAISE EXCEPTION
+      'Consumer group member and size must be specified (Consumer Group Member: %, Consumer Group Size: %)',
+      get_category_messages.consumer_group_member,
+      get_category_messages.consumer_group_size;
+  END IF;
+
+  IF get_category_messages.consumer_group_member IS NOT NULL THEN
+    IF get_category_messages.consumer_group_size < 1 THEN
+      RAISE EXCEPTION 'Consumer group size must be a positive number (Consumer Group Size: %)', get_category_messages.consumer_group_size;
+    END IF;
+
+    IF get_category_messages.consumer_group_member < 0 OR get_category_messages.consumer_group_member >= get_category_messages.consumer_group_size THEN
+      RAISE EXCEPTION
+        'Consumer group member must be between 0 and one less than the group size (Consumer Group Member: %, Consumer Group Size: %)',
+        get_category_messages.consumer_group_member,
+        get_category_messages.consumer_group_size;
+    END IF;
+
+    _command := _command || ' AND mod(@message_store.hash_64(message_store.cardinal_id(stream_name)), $6) = $5';
+  END IF;
+
+  IF get_category_messages.condition IS NOT NULL THEN
+    IF coalesce(current_setting('message_store.sql_condition', true), 'off') = 'off' THEN
+      RAISE EXCEPTION 'Retrieval with SQL condition is not activated';
+    END IF;
+
+    _command := _command || ' AND (' || get_category_messages.condition || ')';
+  END IF;
+
+  _command := _command || ' ORDER BY global_position ASC';
+
+  IF coalesce(get_category_messages.batch_size, 1000) != -1 THEN
+    _command := _command || ' LIMIT $3';
+  END IF;
+
+  RETURN QUERY EXECUTE _command USING
+    get_category_messages.category,
+    coalesce(get_category_messages.position, 1),
+    coalesce(get_category_messages.batch_size, 1000),
+    get_category_messages.correlation,
+    get_category_messages.consumer_group_member,
+    get_category_messages.consumer_group_size;
+END;
+$$ LANGUAGE plpgsql
+VOLATILE;`
+
+const createGetLastStreamMessage = `
+CREATE OR REPLACE FUNCTION message_store.get_last_stream_message(
+  stream_name varchar
+)
+RETURNS SETOF message_store.message
+AS $$
+BEGIN
+  RETURN QUERY
+  SELECT
+    id::varchar,
+    messages.stream_name::varchar,
+    type::varchar,
+    position::bigint,
+    global_position::bigint,
+    data::varchar,
+    metadata::varchar,
+    time::timestamp
+  FROM message_store.messages
+  WHERE messages.stream_name = get_last_stream_message.stream_name
+  ORDER BY position DESC
+  LIMIT 1;
+END;
+$$ LANGUAGE plpgsql
+VOLATILE;`
+
+const createGetLastMessage = `
+CREATE OR REPLACE FUNCTION message_store.get_last_message(
+  stream_name varchar
+)
+RETURNS SETOF message_store.message
+AS $$
+BEGIN
+  RETURN QUERY SELECT * FROM message_store.get_last_stream_message(get_last_message.stream_name);
+END;
+$$ LANGUAGE plpgsql
+VOLATILE;`
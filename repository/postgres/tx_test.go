@@ -0,0 +1,88 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	. "github.com/blackhatbrigade/gomessagestore/repository/postgres"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRepoWithTx(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbError  error
+		rollback bool
+	}{{
+		name: "when the transaction commits, the message is written with it",
+	}, {
+		name:     "when the write fails, the caller can roll the transaction back",
+		dbError:  errors.New("bad things with db happened"),
+		rollback: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			db, mockDb, _ := sqlmock.New()
+			msg := mockMessages[0]
+
+			mockDb.ExpectBegin()
+			mockDb.ExpectExec("UPDATE read_model").WillReturnResult(sqlmock.NewResult(1, 1))
+			expectedExec := mockDb.
+				ExpectExec("SELECT write_message\\(\\$1, \\$2, \\$3, \\$4, \\$5\\)").
+				WithArgs(msg.ID, msg.StreamName, msg.MessageType, msg.Data, msg.Metadata)
+			if test.dbError == nil {
+				expectedExec.WillReturnResult(sqlmock.NewResult(1, 1))
+				mockDb.ExpectCommit()
+			} else {
+				expectedExec.WillReturnError(test.dbError)
+				mockDb.ExpectRollback()
+			}
+
+			ctx := context.Background()
+			tx, err := db.BeginTx(ctx, nil)
+			assert.Nil(err)
+			_, err = tx.ExecContext(ctx, "UPDATE read_model SET seen = true")
+			assert.Nil(err)
+
+			repo := NewPostgresRepository(db, logrus.New()).WithTx(tx)
+			err = repo.WriteMessage(ctx, msg)
+			assert.Equal(test.dbError, err)
+
+			if test.rollback {
+				assert.Nil(tx.Rollback())
+			} else {
+				assert.Nil(tx.Commit())
+			}
+			assert.Nil(mockDb.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPostgresRepoWithExtReadsThroughIt(t *testing.T) {
+	assert := assert.New(t)
+	db, mockDb, _ := sqlmock.New()
+
+	mockDb.ExpectBegin()
+	mockDb.
+		ExpectQuery("SELECT stream_version\\(\\$1\\)").
+		WithArgs("some_type-1").
+		WillReturnRows(sqlmock.NewRows([]string{"stream_version"}).AddRow(4))
+	mockDb.ExpectCommit()
+
+	tx, err := sqlx.NewDb(db, "postgres").Beginx()
+	assert.Nil(err)
+
+	repo := NewPostgresRepositoryWithExt(tx, logrus.New())
+	version, err := repo.GetStreamVersion(context.Background(), "some_type-1")
+	assert.Nil(err)
+	assert.Equal(int64(4), version)
+
+	assert.Nil(tx.Commit())
+	assert.Nil(mockDb.ExpectationsWereMet())
+}
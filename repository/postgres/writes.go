@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/blackhatbrigade/gomessagestore/repository"
 	"github.com/blackhatbrigade/gomessagestore/uuid"
@@ -65,7 +66,7 @@ func (r postgresRepo) writeMessageEitherWay(ctx context.Context, msg *repository
 			}).Debug("about to write message")
 			if _, err := r.dbx.ExecContext(ctx, query, msg.ID, msg.StreamName, msg.MessageType, msg.Data, msg.Metadata, position[0]); err != nil {
 				logrus.WithError(err).Error("Failure in repo_postgres.go::WriteMessageWithExpectedPosition")
-				retChan <- err
+				retChan <- translateWriteError(err)
 				return
 			}
 		} else {
@@ -81,7 +82,7 @@ func (r postgresRepo) writeMessageEitherWay(ctx context.Context, msg *repository
 			}).Debug("about to write message")
 			if _, err := r.dbx.ExecContext(ctx, query, msg.ID, msg.StreamName, msg.MessageType, msg.Data, msg.Metadata); err != nil {
 				logrus.WithError(err).Error("Failure in repo_postgres.go::WriteMessage")
-				retChan <- err
+				retChan <- translateWriteError(err)
 				return
 			}
 		}
@@ -97,3 +98,15 @@ func (r postgresRepo) writeMessageEitherWay(ctx context.Context, msg *repository
 		return nil
 	}
 }
+
+// translateWriteError turns the errors raised by write_message into the errors every repository returns
+func translateWriteError(err error) error {
+	switch {
+	case strings.Contains(err.Error(), "Wrong expected version"): // raised by write_message itself
+		return repository.ErrExpectedVersionFailed
+	case strings.Contains(err.Error(), "duplicate key value violates unique constraint"): // the messages_id index
+		return repository.ErrDuplicateMessageID
+	}
+
+	return err
+}
@@ -54,6 +54,7 @@ func TestPostgresRepoWriteMessage(t *testing.T) {
 			logrusLogger := logrus.New()
 			repo := NewPostgresRepository(db, logrusLogger)
 			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
 
 			if test.msg != nil {
 				expectedExec := mockDb.
@@ -144,6 +145,7 @@ func TestPostgresRepoWriteMessageWithExpectedPosition(t *testing.T) {
 			logrusLogger := logrus.New()
 			repo := NewPostgresRepository(db, logrusLogger)
 			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
 
 			if test.msg != nil {
 				expectedExec := mockDb.
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 //go:generate bash -c "${GOPATH}/bin/mockgen github.com/blackhatbrigade/gomessagestore/repository Repository > mocks/repository.go"
@@ -13,9 +15,65 @@ type Repository interface {
 	WriteMessageWithExpectedPosition(ctx context.Context, message *MessageEnvelope, position int64) error
 	// reads from stream
 	GetAllMessagesInStream(ctx context.Context, streamName string, batchSize int) ([]*MessageEnvelope, error)
-	GetAllMessagesInStreamSince(ctx context.Context, streamName string, globalPosition int64, batchSize int) ([]*MessageEnvelope, error)
+	GetAllMessagesInStreamSince(ctx context.Context, streamName string, globalPosition int64, batchSize int, types ...string) ([]*MessageEnvelope, error) // only messages of the types, when there are any
 	GetLastMessageInStream(ctx context.Context, streamName string) (*MessageEnvelope, error)
+	GetStreamVersion(ctx context.Context, streamName string) (int64, error)                                                          // -1 when the stream has no messages
+	GetStreamVersionAtTime(ctx context.Context, streamName string, t time.Time) (int64, error)                                       // the version of the first message written at or after t, or the next version when there is none
+	GetAllMessagesInStreamBackward(ctx context.Context, streamName string, version int64, batchSize int) ([]*MessageEnvelope, error) // at or before the version, newest first
 	// reads from category
 	GetAllMessagesInCategory(ctx context.Context, category string, batchSize int) ([]*MessageEnvelope, error)
-	GetAllMessagesInCategorySince(ctx context.Context, category string, globalPosition int64, batchSize int) ([]*MessageEnvelope, error)
+	GetAllMessagesInCategorySince(ctx context.Context, category string, globalPosition int64, batchSize int, types ...string) ([]*MessageEnvelope, error) // only messages of the types, when there are any
+	GetAllMessagesInCategoryBackward(ctx context.Context, category string, globalPosition int64, batchSize int) ([]*MessageEnvelope, error)               // at or before the global position, newest first
+	// reads from every category
+	GetAllMessagesSince(ctx context.Context, globalPosition int64, batchSize int, filter CategoryFilter, types ...string) ([]*MessageEnvelope, error) // only messages of the types, when there are any
+	GetGlobalPositionAtTime(ctx context.Context, t time.Time) (int64, error)                                                                          // the global position of the first message written at or after t, or the next global position when there is none
+}
+
+//TxRepository is a Repository that can be bound to a caller's transaction, so messages are written alongside the caller's own changes
+//and only once the transaction commits
+type TxRepository interface {
+	Repository
+	WithTx(tx *sql.Tx) Repository
+}
+
+//CategoryFilter limits which categories are read when reading from every category; an empty filter reads them all
+type CategoryFilter struct {
+	Include []string // when not empty, only messages in these categories are read
+	Exclude []string // messages in these categories are never read
+}
+
+//Matches returns true if messages in the category should be read
+func (filter CategoryFilter) Matches(category string) bool {
+	for _, excluded := range filter.Exclude {
+		if category == excluded {
+			return false
+		}
+	}
+
+	if len(filter.Include) == 0 {
+		return true
+	}
+
+	for _, included := range filter.Include {
+		if category == included {
+			return true
+		}
+	}
+
+	return false
+}
+
+//MatchesType returns true if a message of the type should be read when only reading messages of the types; no types reads every type
+func MatchesType(msgType string, types []string) bool {
+	if len(types) == 0 {
+		return true
+	}
+
+	for _, t := range types {
+		if msgType == t {
+			return true
+		}
+	}
+
+	return false
 }
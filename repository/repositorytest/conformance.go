@@ -0,0 +1,649 @@
+// Package repositorytest holds the behaviour every Repository is expected to share with the Eventide message store.
+// Anyone writing a new backend can run RunConformance from their own tests to check it behaves like the postgres one.
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Factory returns an empty repository; it is called once for each test in the suite
+type Factory func(t *testing.T) repository.Repository
+
+// RunConformance runs every conformance test against repositories created by the factory
+func RunConformance(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repo repository.Repository)
+	}{
+		{"versions start at 0 and global positions increase", testWriteAssignsVersionsAndPositions},
+		{"writing at the expected version", testWriteWithExpectedVersion},
+		{"writing a duplicate ID fails", testWriteDuplicateID},
+		{"reading a stream since a version is inclusive and only returns that stream", testReadStreamSince},
+		{"reading the last message in a stream", testReadLastMessage},
+		{"reading the version of a stream", testReadStreamVersion},
+		{"reading a category since a position is inclusive and only returns that category", testReadCategorySince},
+		{"paging through a stream returns every message once, in order", testPageStream},
+		{"paging through a category returns every message once, in order", testPageCategory},
+		{"command categories are separate from their entity categories", testCommandCategories},
+		{"reading every category since a position, with category filters", testReadAllSince},
+		{"reading a stream or category backward is inclusive and newest first", testReadBackward},
+		{"reading only some types limits batches after filtering", testReadTypes},
+		{"a batch size of 0 reads every message there is", testUnlimitedBatchSize},
+		{"batches of more than 1000 messages are read whole", testLargeBatches},
+		{"times resolve to the first message written at or after them", testPositionsAtTime},
+		{"concurrent writes each get their own version and position", testConcurrentWrites},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.test(t, factory(t))
+		})
+	}
+}
+
+// newMessage builds a message for a stream, with everything needed for it to be written
+func newMessage(stream string, msgType string) *repository.MessageEnvelope {
+	return &repository.MessageEnvelope{
+		ID:          uuid.NewRandom(),
+		StreamName:  stream,
+		MessageType: msgType,
+		Data:        []byte(`{"some":"data"}`),
+		Metadata:    []byte(`{"some":"metadata"}`),
+	}
+}
+
+// write writes messages in order, failing the test on any error
+func write(t *testing.T, repo repository.Repository, msgs ...*repository.MessageEnvelope) {
+	for _, msg := range msgs {
+		if err := repo.WriteMessage(context.Background(), msg); err != nil {
+			t.Fatalf("failed writing message to %s: %s", msg.StreamName, err)
+		}
+	}
+}
+
+// ids picks out the IDs of messages, to compare what was read with what was written
+func ids(msgs []*repository.MessageEnvelope) []uuid.UUID {
+	found := make([]uuid.UUID, len(msgs))
+	for i, msg := range msgs {
+		found[i] = msg.ID
+	}
+
+	return found
+}
+
+func testWriteAssignsVersionsAndPositions(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	first := newMessage("thing-1", "Happened")
+	other := newMessage("other-1", "Happened")
+	second := newMessage("thing-1", "HappenedAgain")
+	write(t, repo, first, other, second)
+
+	msgs, err := repo.GetAllMessagesInStream(ctx, "thing-1", 10)
+	assert.Nil(err)
+	if assert.Len(msgs, 2) {
+		assert.Equal(int64(0), msgs[0].Version)
+		assert.Equal(int64(1), msgs[1].Version)
+		assert.True(msgs[0].GlobalPosition > 0)
+		assert.True(msgs[1].GlobalPosition > msgs[0].GlobalPosition+1) // the other stream's message is between them
+		assert.Equal(first.ID, msgs[0].ID)
+		assert.Equal(first.MessageType, msgs[0].MessageType)
+		assert.Equal(first.StreamName, msgs[0].StreamName)
+		assert.JSONEq(string(first.Data), string(msgs[0].Data))
+		assert.JSONEq(string(first.Metadata), string(msgs[0].Metadata))
+		assert.False(msgs[0].Time.IsZero())
+	}
+
+	msgs, err = repo.GetAllMessagesInStream(ctx, "other-1", 10)
+	assert.Nil(err)
+	if assert.Len(msgs, 1) {
+		assert.Equal(int64(0), msgs[0].Version)
+	}
+}
+
+func testWriteWithExpectedVersion(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	assert.Equal(repository.ErrExpectedVersionFailed, repo.WriteMessageWithExpectedPosition(ctx, newMessage("thing-1", "Happened"), 0))
+	assert.Nil(repo.WriteMessageWithExpectedPosition(ctx, newMessage("thing-1", "Happened"), -1))
+	assert.Equal(repository.ErrExpectedVersionFailed, repo.WriteMessageWithExpectedPosition(ctx, newMessage("thing-1", "Happened"), -1))
+	assert.Nil(repo.WriteMessageWithExpectedPosition(ctx, newMessage("thing-1", "Happened"), 0))
+	assert.Equal(repository.ErrExpectedVersionFailed, repo.WriteMessageWithExpectedPosition(ctx, newMessage("thing-1", "Happened"), 0))
+	assert.Equal(repository.ErrInvalidPosition, repo.WriteMessageWithExpectedPosition(ctx, newMessage("thing-1", "Happened"), -2))
+
+	msgs, err := repo.GetAllMessagesInStream(ctx, "thing-1", 10)
+	assert.Nil(err)
+	assert.Len(msgs, 2)
+}
+
+func testWriteDuplicateID(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	msg := newMessage("thing-1", "Happened")
+	write(t, repo, msg)
+
+	assert.Equal(repository.ErrDuplicateMessageID, repo.WriteMessage(ctx, msg))
+
+	msg.StreamName = "thing-2" // not even in another stream
+	assert.Equal(repository.ErrDuplicateMessageID, repo.WriteMessage(ctx, msg))
+}
+
+func testReadStreamSince(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	stream := []*repository.MessageEnvelope{
+		newMessage("thing-1", "Happened"),
+		newMessage("thing-1", "Happened"),
+		newMessage("thing-1", "Happened"),
+	}
+	// plenty of other messages so global positions run well past the stream's versions
+	for i := 0; i < 5; i++ {
+		write(t, repo, newMessage("thing-2", "Happened"))
+	}
+	write(t, repo, stream[0])
+	write(t, repo, newMessage("thing-2", "Happened"))
+	write(t, repo, stream[1], stream[2])
+
+	msgs, err := repo.GetAllMessagesInStreamSince(ctx, "thing-1", 1, 10)
+	assert.Nil(err)
+	assert.Equal(ids(stream[1:]), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInStreamSince(ctx, "thing-1", 0, 2)
+	assert.Nil(err)
+	assert.Equal(ids(stream[:2]), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInStreamSince(ctx, "thing-1", 3, 10)
+	assert.Nil(err)
+	assert.Empty(msgs)
+
+	msgs, err = repo.GetAllMessagesInStream(ctx, "nothing-1", 10)
+	assert.Nil(err)
+	assert.Empty(msgs)
+
+	_, err = repo.GetAllMessagesInStreamSince(ctx, "", 0, 10)
+	assert.Equal(repository.ErrInvalidStreamName, err)
+
+	_, err = repo.GetAllMessagesInStreamSince(ctx, "thing-1", 0, -1)
+	assert.Equal(repository.ErrNegativeBatchSize, err)
+}
+
+func testReadLastMessage(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	msg, err := repo.GetLastMessageInStream(ctx, "thing-1")
+	assert.Nil(err)
+	assert.Nil(msg)
+
+	last := newMessage("thing-1", "HappenedLast")
+	write(t, repo, newMessage("thing-1", "Happened"), last, newMessage("thing-2", "Happened"))
+
+	msg, err = repo.GetLastMessageInStream(ctx, "thing-1")
+	assert.Nil(err)
+	if assert.NotNil(msg) {
+		assert.Equal(last.ID, msg.ID)
+		assert.Equal(int64(1), msg.Version)
+	}
+
+	_, err = repo.GetLastMessageInStream(ctx, "")
+	assert.Equal(repository.ErrInvalidStreamName, err)
+}
+
+func testReadStreamVersion(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	version, err := repo.GetStreamVersion(ctx, "thing-1")
+	assert.Nil(err)
+	assert.Equal(int64(-1), version)
+
+	write(t, repo, newMessage("thing-1", "Happened"), newMessage("thing-2", "Happened"), newMessage("thing-1", "Happened"))
+
+	version, err = repo.GetStreamVersion(ctx, "thing-1")
+	assert.Nil(err)
+	assert.Equal(int64(1), version)
+
+	version, err = repo.GetStreamVersion(ctx, "thing-2")
+	assert.Nil(err)
+	assert.Equal(int64(0), version)
+
+	_, err = repo.GetStreamVersion(ctx, "")
+	assert.Equal(repository.ErrInvalidStreamName, err)
+}
+
+func testReadCategorySince(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	category := []*repository.MessageEnvelope{
+		newMessage("thing-1", "Happened"),
+		newMessage("thing-2", "Happened"),
+		newMessage("thing", "Happened"), // a stream with no ID is still in the category
+		newMessage("thing-1", "Happened"),
+	}
+	write(t, repo, category[0], newMessage("otherThing-1", "Happened"), category[1])
+	write(t, repo, newMessage("thing:command-1", "DoIt"), category[2], category[3])
+
+	msgs, err := repo.GetAllMessagesInCategory(ctx, "thing", 10)
+	assert.Nil(err)
+	assert.Equal(ids(category), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInCategorySince(ctx, "thing", msgs[1].GlobalPosition, 2)
+	assert.Nil(err)
+	assert.Equal(ids(category[1:3]), ids(msgs))
+
+	_, err = repo.GetAllMessagesInCategorySince(ctx, "", 0, 10)
+	assert.Equal(repository.ErrBlankCategory, err)
+
+	_, err = repo.GetAllMessagesInCategorySince(ctx, "thing-1", 0, 10)
+	assert.Equal(repository.ErrInvalidCategory, err)
+
+	_, err = repo.GetAllMessagesInCategorySince(ctx, "thing", 0, -1)
+	assert.Equal(repository.ErrNegativeBatchSize, err)
+}
+
+func testPageStream(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	var written []*repository.MessageEnvelope
+	for i := 0; i < 7; i++ {
+		msg := newMessage("thing-1", fmt.Sprintf("Happened%d", i))
+		written = append(written, msg)
+		write(t, repo, msg, newMessage("thing-2", "Happened"))
+	}
+
+	var read []*repository.MessageEnvelope
+	version := int64(0)
+	for pages := 0; pages < 10; pages++ {
+		msgs, err := repo.GetAllMessagesInStreamSince(ctx, "thing-1", version, 3)
+		assert.Nil(err)
+		assert.True(len(msgs) <= 3)
+		if len(msgs) == 0 {
+			break
+		}
+
+		read = append(read, msgs...)
+		version = msgs[len(msgs)-1].Version + 1
+	}
+
+	assert.Equal(ids(written), ids(read))
+	for i, msg := range read {
+		assert.Equal(int64(i), msg.Version)
+		assert.Equal(written[i].MessageType, msg.MessageType)
+	}
+}
+
+func testPageCategory(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	var written []*repository.MessageEnvelope
+	for i := 0; i < 7; i++ {
+		msg := newMessage(fmt.Sprintf("thing-%d", i%3), "Happened")
+		written = append(written, msg)
+		write(t, repo, msg, newMessage("otherThing-1", "Happened"))
+	}
+
+	var read []*repository.MessageEnvelope
+	position := int64(0)
+	for pages := 0; pages < 10; pages++ {
+		msgs, err := repo.GetAllMessagesInCategorySince(ctx, "thing", position, 3)
+		assert.Nil(err)
+		assert.True(len(msgs) <= 3)
+		if len(msgs) == 0 {
+			break
+		}
+
+		read = append(read, msgs...)
+		position = msgs[len(msgs)-1].GlobalPosition + 1
+	}
+
+	assert.Equal(ids(written), ids(read))
+	for i := 1; i < len(read); i++ {
+		assert.True(read[i].GlobalPosition > read[i-1].GlobalPosition)
+	}
+}
+
+func testCommandCategories(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	event := newMessage("thing-1", "Happened")
+	commands := []*repository.MessageEnvelope{
+		newMessage("thing:command-1", "DoIt"),
+		newMessage("thing:command", "DoItToEverything"),
+		newMessage("thing:command-2", "DoIt"),
+	}
+	write(t, repo, commands[0], event, commands[1], newMessage("thing:command+position-1", "Position"), commands[2])
+
+	msgs, err := repo.GetAllMessagesInCategory(ctx, "thing:command", 10)
+	assert.Nil(err)
+	assert.Equal(ids(commands), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInCategory(ctx, "thing", 10)
+	assert.Nil(err)
+	assert.Equal(ids([]*repository.MessageEnvelope{event}), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInStream(ctx, "thing:command-1", 10)
+	assert.Nil(err)
+	assert.Equal(ids(commands[:1]), ids(msgs))
+}
+
+func testReadAllSince(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	all := []*repository.MessageEnvelope{
+		newMessage("thing-1", "Happened"),
+		newMessage("otherThing-1", "Happened"),
+		newMessage("thing:command-1", "DoIt"),
+		newMessage("thing-2", "Happened"),
+		newMessage("audit", "Audited"),
+	}
+	write(t, repo, all...)
+
+	msgs, err := repo.GetAllMessagesSince(ctx, 0, 10, repository.CategoryFilter{})
+	assert.Nil(err)
+	assert.Equal(ids(all), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesSince(ctx, msgs[1].GlobalPosition, 2, repository.CategoryFilter{})
+	assert.Nil(err)
+	assert.Equal(ids(all[1:3]), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesSince(ctx, 0, 10, repository.CategoryFilter{Include: []string{"thing", "audit"}})
+	assert.Nil(err)
+	assert.Equal(ids([]*repository.MessageEnvelope{all[0], all[3], all[4]}), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesSince(ctx, 0, 10, repository.CategoryFilter{Exclude: []string{"thing:command", "audit"}})
+	assert.Nil(err)
+	assert.Equal(ids([]*repository.MessageEnvelope{all[0], all[1], all[3]}), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesSince(ctx, 0, 1, repository.CategoryFilter{Include: []string{"thing"}, Exclude: []string{"otherThing"}})
+	assert.Nil(err)
+	assert.Equal(ids(all[:1]), ids(msgs))
+
+	_, err = repo.GetAllMessagesSince(ctx, 0, -1, repository.CategoryFilter{})
+	assert.Equal(repository.ErrNegativeBatchSize, err)
+}
+
+func testReadBackward(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	all := []*repository.MessageEnvelope{
+		newMessage("thing-1", "Happened"),
+		newMessage("thing-2", "Happened"),
+		newMessage("otherThing-1", "Happened"),
+		newMessage("thing-1", "Happened"),
+		newMessage("thing-1", "Happened"),
+	}
+	write(t, repo, all...)
+	newestFirst := func(msgs ...*repository.MessageEnvelope) []uuid.UUID {
+		return ids(msgs)
+	}
+
+	msgs, err := repo.GetAllMessagesInStreamBackward(ctx, "thing-1", 100, 10)
+	assert.Nil(err)
+	assert.Equal(newestFirst(all[4], all[3], all[0]), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInStreamBackward(ctx, "thing-1", 1, 10)
+	assert.Nil(err)
+	assert.Equal(newestFirst(all[3], all[0]), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInStreamBackward(ctx, "thing-1", 2, 2)
+	assert.Nil(err)
+	assert.Equal(newestFirst(all[4], all[3]), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInStreamBackward(ctx, "thing-1", -1, 10)
+	assert.Nil(err)
+	assert.Len(msgs, 0)
+
+	msgs, err = repo.GetAllMessagesInCategoryBackward(ctx, "thing", 1000, 10)
+	assert.Nil(err)
+	assert.Equal(newestFirst(all[4], all[3], all[1], all[0]), ids(msgs))
+
+	read, err := repo.GetAllMessagesInStreamSince(ctx, "thing-1", 1, 1)
+	assert.Nil(err)
+	msgs, err = repo.GetAllMessagesInCategoryBackward(ctx, "thing", read[0].GlobalPosition, 2)
+	assert.Nil(err)
+	assert.Equal(newestFirst(all[3], all[1]), ids(msgs))
+
+	_, err = repo.GetAllMessagesInStreamBackward(ctx, "", 0, 10)
+	assert.Equal(repository.ErrInvalidStreamName, err)
+
+	_, err = repo.GetAllMessagesInStreamBackward(ctx, "thing-1", 0, -1)
+	assert.Equal(repository.ErrNegativeBatchSize, err)
+
+	_, err = repo.GetAllMessagesInCategoryBackward(ctx, "thing-1", 0, 10)
+	assert.Equal(repository.ErrInvalidCategory, err)
+
+	_, err = repo.GetAllMessagesInCategoryBackward(ctx, "", 0, 10)
+	assert.Equal(repository.ErrBlankCategory, err)
+}
+
+func testUnlimitedBatchSize(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	other := newMessage("a-1", "Happened") // not in anything read, so reads have to carry on past it
+	all := []*repository.MessageEnvelope{
+		newMessage("b-1", "Happened"),
+		newMessage("b-1", "Happened"),
+		newMessage("b-2", "Happened"),
+	}
+	write(t, repo, other)
+	write(t, repo, all...)
+
+	msgs, err := repo.GetAllMessagesInStream(ctx, "b-1", 0)
+	assert.Nil(err)
+	assert.Equal(ids(all[:2]), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInStreamSince(ctx, "b-1", 0, 0, "Happened")
+	assert.Nil(err)
+	assert.Equal(ids(all[:2]), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInCategory(ctx, "b", 0)
+	assert.Nil(err)
+	assert.Equal(ids(all), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInCategorySince(ctx, "b", 0, 0, "Happened")
+	assert.Nil(err)
+	assert.Equal(ids(all), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesSince(ctx, 0, 0, repository.CategoryFilter{Include: []string{"b"}})
+	assert.Nil(err)
+	assert.Equal(ids(all), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInStreamBackward(ctx, "b-1", 100, 0)
+	assert.Nil(err)
+	assert.Equal(ids([]*repository.MessageEnvelope{all[1], all[0]}), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInCategoryBackward(ctx, "b", 100, 0)
+	assert.Nil(err)
+	assert.Equal(ids([]*repository.MessageEnvelope{all[2], all[1], all[0]}), ids(msgs))
+}
+
+func testLargeBatches(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	// more than a page of a remote store, so reads there have to take several pages and still stop at the batch size
+	all := make([]*repository.MessageEnvelope, 1100)
+	for i := range all {
+		all[i] = newMessage("big-1", "Happened")
+	}
+	write(t, repo, all...)
+
+	backward := make([]*repository.MessageEnvelope, len(all))
+	for i, msg := range all {
+		backward[len(all)-1-i] = msg
+	}
+
+	msgs, err := repo.GetAllMessagesInStream(ctx, "big-1", 0)
+	assert.Nil(err)
+	assert.Equal(ids(all), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInStreamSince(ctx, "big-1", 10, 1050)
+	assert.Nil(err)
+	assert.Equal(ids(all[10:1060]), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInCategory(ctx, "big", 0)
+	assert.Nil(err)
+	assert.Equal(ids(all), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInCategorySince(ctx, "big", 1, 1050, "Happened")
+	assert.Nil(err)
+	assert.Equal(ids(all[:1050]), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesSince(ctx, 0, 1099, repository.CategoryFilter{})
+	assert.Nil(err)
+	assert.Equal(ids(all[:1099]), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInStreamBackward(ctx, "big-1", 10000, 0)
+	assert.Nil(err)
+	assert.Equal(ids(backward), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInCategoryBackward(ctx, "big", 10000, 1050)
+	assert.Nil(err)
+	assert.Equal(ids(backward[:1050]), ids(msgs))
+}
+
+func testReadTypes(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	all := []*repository.MessageEnvelope{
+		newMessage("thing-1", "Created"),
+		newMessage("thing-1", "Ignored"),
+		newMessage("thing-2", "Ignored"),
+		newMessage("thing-1", "Ignored"),
+		newMessage("thing-1", "Updated"),
+		newMessage("thing-2", "Updated"),
+		newMessage("otherThing-1", "Updated"),
+	}
+	write(t, repo, all...)
+
+	msgs, err := repo.GetAllMessagesInStreamSince(ctx, "thing-1", 0, 10, "Created", "Updated")
+	assert.Nil(err)
+	assert.Equal(ids([]*repository.MessageEnvelope{all[0], all[4]}), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInStreamSince(ctx, "thing-1", 1, 1, "Created", "Updated")
+	assert.Nil(err)
+	assert.Equal(ids(all[4:5]), ids(msgs))
+	if assert.Len(msgs, 1) {
+		assert.Equal(int64(3), msgs[0].Version)
+	}
+
+	msgs, err = repo.GetAllMessagesInStreamSince(ctx, "thing-1", 0, 10, "Deleted")
+	assert.Nil(err)
+	assert.Len(msgs, 0)
+
+	msgs, err = repo.GetAllMessagesInCategorySince(ctx, "thing", 0, 2, "Updated")
+	assert.Nil(err)
+	assert.Equal(ids(all[4:6]), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesInCategorySince(ctx, "thing", msgs[0].GlobalPosition+1, 10, "Created", "Updated")
+	assert.Nil(err)
+	assert.Equal(ids(all[5:6]), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesSince(ctx, 0, 10, repository.CategoryFilter{}, "Updated")
+	assert.Nil(err)
+	assert.Equal(ids(all[4:]), ids(msgs))
+
+	msgs, err = repo.GetAllMessagesSince(ctx, 0, 10, repository.CategoryFilter{Exclude: []string{"thing"}}, "Created", "Updated")
+	assert.Nil(err)
+	assert.Equal(ids(all[6:]), ids(msgs))
+}
+
+func testPositionsAtTime(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	write(t, repo, newMessage("thing-1", "Happened"), newMessage("thing-2", "Happened"))
+	time.Sleep(10 * time.Millisecond)
+	write(t, repo, newMessage("thing-1", "Happened"), newMessage("thing-2", "Happened"))
+
+	msgs, err := repo.GetAllMessagesSince(ctx, 0, 10, repository.CategoryFilter{})
+	assert.Nil(err)
+	if !assert.Len(msgs, 4) {
+		return
+	}
+	afterGap := msgs[1].Time.Add(time.Millisecond)
+
+	position, err := repo.GetGlobalPositionAtTime(ctx, time.Time{})
+	assert.Nil(err)
+	assert.Equal(msgs[0].GlobalPosition, position)
+
+	position, err = repo.GetGlobalPositionAtTime(ctx, msgs[2].Time)
+	assert.Nil(err)
+	assert.Equal(msgs[2].GlobalPosition, position)
+
+	position, err = repo.GetGlobalPositionAtTime(ctx, afterGap)
+	assert.Nil(err)
+	assert.Equal(msgs[2].GlobalPosition, position)
+
+	position, err = repo.GetGlobalPositionAtTime(ctx, msgs[3].Time.Add(time.Hour))
+	assert.Nil(err)
+	assert.Equal(msgs[3].GlobalPosition+1, position)
+
+	version, err := repo.GetStreamVersionAtTime(ctx, "thing-1", time.Time{})
+	assert.Nil(err)
+	assert.Equal(int64(0), version)
+
+	version, err = repo.GetStreamVersionAtTime(ctx, "thing-1", afterGap)
+	assert.Nil(err)
+	assert.Equal(int64(1), version)
+
+	version, err = repo.GetStreamVersionAtTime(ctx, "thing-1", msgs[3].Time.Add(time.Hour))
+	assert.Nil(err)
+	assert.Equal(int64(2), version)
+
+	version, err = repo.GetStreamVersionAtTime(ctx, "thing-3", time.Time{})
+	assert.Nil(err)
+	assert.Equal(int64(0), version)
+
+	_, err = repo.GetStreamVersionAtTime(ctx, "", time.Time{})
+	assert.Equal(repository.ErrInvalidStreamName, err)
+}
+
+func testConcurrentWrites(t *testing.T, repo repository.Repository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	writers := 10
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.Nil(repo.WriteMessage(ctx, newMessage("thing-1", fmt.Sprintf("Happened%d", i))))
+			_, err := repo.GetAllMessagesInCategory(ctx, "thing", 100)
+			assert.Nil(err)
+		}(i)
+	}
+	wg.Wait()
+
+	msgs, err := repo.GetAllMessagesInStream(ctx, "thing-1", 100)
+	assert.Nil(err)
+	if assert.Len(msgs, writers) {
+		for i, msg := range msgs {
+			assert.Equal(int64(i), msg.Version)
+			if i > 0 {
+				assert.True(msg.GlobalPosition > msgs[i-1].GlobalPosition)
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+)
+
+func (r *sqliteRepo) GetAllMessagesSince(ctx context.Context, globalPosition int64, batchSize int, filter repository.CategoryFilter, types ...string) ([]*repository.MessageEnvelope, error) {
+	if batchSize < 0 {
+		return nil, repository.ErrNegativeBatchSize
+	}
+
+	query := "SELECT " + columns + " FROM messages WHERE global_position >= ?"
+	args := []interface{}{globalPosition}
+	if len(filter.Include) > 0 {
+		query += " AND " + categoryOf + " IN (?" + strings.Repeat(", ?", len(filter.Include)-1) + ")"
+		for _, category := range filter.Include {
+			args = append(args, category)
+		}
+	}
+	if len(filter.Exclude) > 0 {
+		query += " AND " + categoryOf + " NOT IN (?" + strings.Repeat(", ?", len(filter.Exclude)-1) + ")"
+		for _, category := range filter.Exclude {
+			args = append(args, category)
+		}
+	}
+	condition, args := ofTypes(types, args)
+	query += condition + " ORDER BY global_position LIMIT ?"
+	args = append(args, limit(batchSize))
+
+	msgs := []*repository.MessageEnvelope{}
+	if err := r.dbx.SelectContext(ctx, &msgs, query, args...); err != nil {
+		r.log.WithError(err).Error("Failure in sqlite/read_all.go::GetAllMessagesSince")
+		return nil, err
+	}
+
+	r.log.Debugf("read %d messages from every category", len(msgs))
+
+	return msgs, nil
+}
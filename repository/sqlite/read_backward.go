@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+)
+
+func (r *sqliteRepo) GetAllMessagesInStreamBackward(ctx context.Context, streamName string, version int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+	if streamName == "" {
+		return nil, repository.ErrInvalidStreamName
+	}
+	if batchSize < 0 {
+		return nil, repository.ErrNegativeBatchSize
+	}
+
+	msgs := []*repository.MessageEnvelope{}
+	query := "SELECT " + columns + " FROM messages WHERE stream_name = ? AND position <= ? ORDER BY position DESC LIMIT ?"
+	if err := r.dbx.SelectContext(ctx, &msgs, query, streamName, version, limit(batchSize)); err != nil {
+		r.log.WithError(err).Error("Failure in sqlite/read_backward.go::GetAllMessagesInStreamBackward")
+		return nil, err
+	}
+
+	r.log.Debugf("read %d messages backward from stream %s", len(msgs), streamName)
+
+	return msgs, nil
+}
+
+func (r *sqliteRepo) GetAllMessagesInCategoryBackward(ctx context.Context, category string, globalPosition int64, batchSize int) ([]*repository.MessageEnvelope, error) {
+	if category == "" {
+		return nil, repository.ErrBlankCategory
+	}
+	if batchSize < 0 {
+		return nil, repository.ErrNegativeBatchSize
+	}
+	if strings.Contains(category, "-") {
+		return nil, repository.ErrInvalidCategory
+	}
+
+	msgs := []*repository.MessageEnvelope{}
+	query := "SELECT " + columns + " FROM messages WHERE " + categoryOf + " = ? AND global_position <= ? ORDER BY global_position DESC LIMIT ?"
+	if err := r.dbx.SelectContext(ctx, &msgs, query, category, globalPosition, limit(batchSize)); err != nil {
+		r.log.WithError(err).Error("Failure in sqlite/read_backward.go::GetAllMessagesInCategoryBackward")
+		return nil, err
+	}
+
+	r.log.Debugf("read %d messages backward from category %s", len(msgs), category)
+
+	return msgs, nil
+}
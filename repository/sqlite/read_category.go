@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+)
+
+func (r *sqliteRepo) GetAllMessagesInCategory(ctx context.Context, category string, batchSize int) ([]*repository.MessageEnvelope, error) {
+	return r.GetAllMessagesInCategorySince(ctx, category, 0, batchSize)
+}
+
+func (r *sqliteRepo) GetAllMessagesInCategorySince(ctx context.Context, category string, globalPosition int64, batchSize int, types ...string) ([]*repository.MessageEnvelope, error) {
+	if category == "" {
+		return nil, repository.ErrBlankCategory
+	}
+	if batchSize < 0 {
+		return nil, repository.ErrNegativeBatchSize
+	}
+	if strings.Contains(category, "-") {
+		return nil, repository.ErrInvalidCategory
+	}
+
+	msgs := []*repository.MessageEnvelope{}
+	condition, args := ofTypes(types, []interface{}{category, globalPosition})
+	query := "SELECT " + columns + " FROM messages WHERE " + categoryOf + " = ? AND global_position >= ?" + condition + " ORDER BY global_position LIMIT ?"
+	if err := r.dbx.SelectContext(ctx, &msgs, query, append(args, limit(batchSize))...); err != nil {
+		r.log.WithError(err).Error("Failure in sqlite/read_category.go::GetAllMessagesInCategorySince")
+		return nil, err
+	}
+
+	r.log.Debugf("read %d messages from category %s", len(msgs), category)
+
+	return msgs, nil
+}
@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+)
+
+// columns are the same columns the message store's read functions return
+const columns = "id, stream_name, type, position, global_position, data, metadata, time"
+
+// streamVersionQuery is the message store's stream_version() function, but with -1 for a stream with no messages
+const streamVersionQuery = "SELECT COALESCE(MAX(position), -1) FROM messages WHERE stream_name = ?"
+
+func (r *sqliteRepo) GetAllMessagesInStream(ctx context.Context, streamName string, batchSize int) ([]*repository.MessageEnvelope, error) {
+	return r.GetAllMessagesInStreamSince(ctx, streamName, 0, batchSize)
+}
+
+func (r *sqliteRepo) GetLastMessageInStream(ctx context.Context, streamName string) (*repository.MessageEnvelope, error) {
+	if streamName == "" {
+		return nil, repository.ErrInvalidStreamName
+	}
+
+	msgs := []*repository.MessageEnvelope{}
+	query := "SELECT " + columns + " FROM messages WHERE stream_name = ? ORDER BY position DESC LIMIT 1"
+	if err := r.dbx.SelectContext(ctx, &msgs, query, streamName); err != nil {
+		r.log.WithError(err).Error("Failure in sqlite/read_stream.go::GetLastMessageInStream")
+		return nil, err
+	}
+
+	if len(msgs) == 0 {
+		return nil, nil
+	}
+
+	return msgs[0], nil
+}
+
+func (r *sqliteRepo) GetStreamVersion(ctx context.Context, streamName string) (int64, error) {
+	if streamName == "" {
+		return 0, repository.ErrInvalidStreamName
+	}
+
+	var version int64
+	if err := r.dbx.GetContext(ctx, &version, streamVersionQuery, streamName); err != nil {
+		r.log.WithError(err).Error("Failure in sqlite/read_stream.go::GetStreamVersion")
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func (r *sqliteRepo) GetAllMessagesInStreamSince(ctx context.Context, streamName string, version int64, batchSize int, types ...string) ([]*repository.MessageEnvelope, error) {
+	if streamName == "" {
+		return nil, repository.ErrInvalidStreamName
+	}
+	if batchSize < 0 {
+		return nil, repository.ErrNegativeBatchSize
+	}
+
+	msgs := []*repository.MessageEnvelope{}
+	condition, args := ofTypes(types, []interface{}{streamName, version})
+	query := "SELECT " + columns + " FROM messages WHERE stream_name = ? AND position >= ?" + condition + " ORDER BY position LIMIT ?"
+	if err := r.dbx.SelectContext(ctx, &msgs, query, append(args, limit(batchSize))...); err != nil {
+		r.log.WithError(err).Error("Failure in sqlite/read_stream.go::GetAllMessagesInStreamSince")
+		return nil, err
+	}
+
+	r.log.Debugf("read %d messages from stream %s", len(msgs), streamName)
+
+	return msgs, nil
+}
+
+// limit turns a batch size into a LIMIT, where a batch size of 0 means no limit at all
+func limit(batchSize int) int {
+	if batchSize == 0 {
+		return -1
+	}
+
+	return batchSize
+}
+
+// ofTypes returns the condition that limits a query to messages of the types, adding them to the arguments; no types reads every type
+func ofTypes(types []string, args []interface{}) (string, []interface{}) {
+	if len(types) == 0 {
+		return "", args
+	}
+
+	for _, t := range types {
+		args = append(args, t)
+	}
+
+	return " AND type IN (?" + strings.Repeat(", ?", len(types)-1) + ")", args
+}
@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+)
+
+// Times are written in UTC in the driver's one text format, which sorts the same way the times do, so they're compared as they are
+
+func (r *sqliteRepo) GetStreamVersionAtTime(ctx context.Context, streamName string, t time.Time) (int64, error) {
+	if streamName == "" {
+		return 0, repository.ErrInvalidStreamName
+	}
+
+	var version int64
+	query := "SELECT COALESCE((SELECT MIN(position) FROM messages WHERE stream_name = ? AND time >= ?), (" + streamVersionQuery + ") + 1)"
+	if err := r.dbx.GetContext(ctx, &version, query, streamName, t.UTC(), streamName); err != nil {
+		r.log.WithError(err).Error("Failure in sqlite/read_time.go::GetStreamVersionAtTime")
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func (r *sqliteRepo) GetGlobalPositionAtTime(ctx context.Context, t time.Time) (int64, error) {
+	var position int64
+	query := "SELECT COALESCE((SELECT MIN(global_position) FROM messages WHERE time >= ?), (SELECT COALESCE(MAX(global_position), 0) + 1 FROM messages))"
+	if err := r.dbx.GetContext(ctx, &position, query, t.UTC()); err != nil {
+		r.log.WithError(err).Error("Failure in sqlite/read_time.go::GetGlobalPositionAtTime")
+		return 0, err
+	}
+
+	return position, nil
+}
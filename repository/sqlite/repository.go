@@ -0,0 +1,64 @@
+// Package sqlite is a Repository backed by a SQLite database, for local development, CLI tools and single binary deployments.
+//
+// The messages table matches the Eventide message store's, and versions, global positions, expected versions and
+// categories all behave the same as they do with Postgres. Open the database with the mattn/go-sqlite3 driver:
+//
+//	db, err := sql.Open("sqlite3", "file:messages.db?_txlock=immediate")
+//
+// Writes from one repository are serialized; when more than one process writes to the same file, _txlock=immediate
+// keeps their transactions from deadlocking each other.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3" // registers the sqlite3 driver
+	"github.com/sirupsen/logrus"
+)
+
+// categoryOf is the SQL for the message store's category() function: everything in a stream name before the first hyphen
+const categoryOf = "substr(stream_name, 1, instr(stream_name || '-', '-') - 1)"
+
+// schema creates the messages table and its indexes, unless they already exist
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS messages (
+		global_position INTEGER PRIMARY KEY AUTOINCREMENT,
+		position INTEGER NOT NULL,
+		time DATETIME NOT NULL,
+		stream_name TEXT NOT NULL,
+		type TEXT NOT NULL,
+		data TEXT,
+		metadata TEXT,
+		id TEXT NOT NULL
+	)`,
+	"CREATE UNIQUE INDEX IF NOT EXISTS messages_id ON messages (id)",
+	"CREATE UNIQUE INDEX IF NOT EXISTS messages_stream ON messages (stream_name, position)",
+	"CREATE INDEX IF NOT EXISTS messages_category ON messages (" + categoryOf + ", global_position)",
+	"CREATE INDEX IF NOT EXISTS messages_time ON messages (time)",
+}
+
+//NewSqliteRepository creates a sqlite implementation for the messagestore repo, creating the messages table if it doesn't exist yet
+func NewSqliteRepository(ctx context.Context, db *sql.DB, log logrus.FieldLogger) (repository.Repository, error) {
+	r := new(sqliteRepo)
+	r.dbx = sqlx.NewDb(db, "sqlite3")
+	r.log = log
+
+	for _, statement := range schema {
+		if _, err := r.dbx.ExecContext(ctx, statement); err != nil {
+			log.WithError(err).Error("Failure in sqlite/repository.go::NewSqliteRepository")
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+type sqliteRepo struct {
+	dbx        *sqlx.DB
+	log        logrus.FieldLogger
+	writeMutex sync.Mutex // sqlite only allows one writer at a time, so don't make them fight over it
+}
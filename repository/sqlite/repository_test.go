@@ -0,0 +1,85 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/repository/repositorytest"
+	. "github.com/blackhatbrigade/gomessagestore/repository/sqlite"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// openDB opens a new database in a temporary directory, and returns a function that removes it again
+func openDB(t *testing.T) (*sql.DB, func()) {
+	dir, err := ioutil.TempDir("", "gomessagestore")
+	if err != nil {
+		t.Fatalf("failed to create a temporary directory: %s", err)
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(dir, "messages.db")+"?_txlock=immediate")
+	if err != nil {
+		t.Fatalf("failed to open database: %s", err)
+	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestSqliteRepoConformance(t *testing.T) {
+	var cleanups []func()
+	defer func() {
+		for _, cleanup := range cleanups {
+			cleanup()
+		}
+	}()
+
+	repositorytest.RunConformance(t, func(t *testing.T) repository.Repository {
+		db, cleanup := openDB(t)
+		cleanups = append(cleanups, cleanup)
+
+		repo, err := NewSqliteRepository(context.Background(), db, logrus.New())
+		if err != nil {
+			t.Fatalf("failed to create repository: %s", err)
+		}
+
+		return repo
+	})
+}
+
+func TestSqliteRepoKeepsMessagesWhenReopened(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	db, cleanup := openDB(t)
+	defer cleanup()
+
+	repo, err := NewSqliteRepository(ctx, db, logrus.New())
+	assert.Nil(err)
+	msg := &repository.MessageEnvelope{
+		ID:          uuid.NewRandom(),
+		StreamName:  "thing-1",
+		MessageType: "Happened",
+		Data:        []byte(`{"some":"data"}`),
+	}
+	assert.Nil(repo.WriteMessage(ctx, msg))
+
+	repo, err = NewSqliteRepository(ctx, db, logrus.New()) // the table is already there
+	assert.Nil(err)
+
+	last, err := repo.GetLastMessageInStream(ctx, "thing-1")
+	assert.Nil(err)
+	if assert.NotNil(last) {
+		assert.Equal(msg.ID, last.ID)
+		assert.Equal(int64(1), last.GlobalPosition)
+		assert.Equal(`{"some":"data"}`, string(last.Data))
+		assert.Nil(last.Metadata)
+	}
+}
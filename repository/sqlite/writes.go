@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+)
+
+func (r *sqliteRepo) WriteMessage(ctx context.Context, msg *repository.MessageEnvelope) error {
+	return r.writeMessageEitherWay(ctx, msg)
+}
+
+func (r *sqliteRepo) WriteMessageWithExpectedPosition(ctx context.Context, msg *repository.MessageEnvelope, position int64) error {
+	return r.writeMessageEitherWay(ctx, msg, position)
+}
+
+// writeMessageEitherWay does what the message store's write_message() function does, in a single transaction
+func (r *sqliteRepo) writeMessageEitherWay(ctx context.Context, msg *repository.MessageEnvelope, position ...int64) error {
+	if msg == nil {
+		return repository.ErrNilMessage
+	}
+
+	if msg.ID == uuid.Nil {
+		return repository.ErrMessageNoID
+	}
+
+	if msg.StreamName == "" {
+		return repository.ErrInvalidStreamName
+	}
+
+	if len(position) > 0 && position[0] < -1 {
+		return repository.ErrInvalidPosition
+	}
+
+	r.writeMutex.Lock()
+	defer r.writeMutex.Unlock()
+
+	tx, err := r.dbx.BeginTxx(ctx, nil)
+	if err != nil {
+		r.log.WithError(err).Error("Failure in sqlite/writes.go::writeMessageEitherWay")
+		return err
+	}
+	defer tx.Rollback() // does nothing once committed
+
+	var version int64
+	if err := tx.GetContext(ctx, &version, streamVersionQuery, msg.StreamName); err != nil {
+		r.log.WithError(err).Error("Failure in sqlite/writes.go::writeMessageEitherWay")
+		return err
+	}
+
+	if len(position) > 0 && position[0] != version {
+		r.log.WithError(repository.ErrExpectedVersionFailed).Debugf("stream %s is at version %d, not %d", msg.StreamName, version, position[0])
+		return repository.ErrExpectedVersionFailed
+	}
+
+	var existing int
+	err = tx.GetContext(ctx, &existing, "SELECT 1 FROM messages WHERE id = ?", msg.ID)
+	if err == nil {
+		return repository.ErrDuplicateMessageID
+	} else if err != sql.ErrNoRows {
+		r.log.WithError(err).Error("Failure in sqlite/writes.go::writeMessageEitherWay")
+		return err
+	}
+
+	query := "INSERT INTO messages (id, stream_name, type, position, data, metadata, time) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	if _, err := tx.ExecContext(ctx, query, msg.ID, msg.StreamName, msg.MessageType, version+1, nullableJSON(msg.Data), nullableJSON(msg.Metadata), time.Now().UTC()); err != nil {
+		r.log.WithError(err).Error("Failure in sqlite/writes.go::writeMessageEitherWay")
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		r.log.WithError(err).Error("Failure in sqlite/writes.go::writeMessageEitherWay")
+		return err
+	}
+
+	r.log.Debugf("wrote successfully to stream %s", msg.StreamName)
+
+	return nil
+}
+
+// nullableJSON stores JSON as text, so the database stays readable from the sqlite3 shell, and missing JSON as NULL
+func nullableJSON(data []byte) interface{} {
+	if data == nil {
+		return nil
+	}
+
+	return string(data)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"strings"
+)
+
+// These mirror the message store's functions of the same names, for repositories that don't have them in their database
+
+//Category returns the category of a stream: everything before the first hyphen
+func Category(streamName string) string {
+	return strings.SplitN(streamName, "-", 2)[0]
+}
+
+//ID returns everything in a stream name after the first hyphen, or "" when there's no hyphen
+func ID(streamName string) string {
+	pieces := strings.SplitN(streamName, "-", 2)
+	if len(pieces) < 2 {
+		return ""
+	}
+
+	return pieces[1]
+}
+
+//CardinalID returns the first of a stream's IDs, where a compound ID is separated by plus signs
+func CardinalID(streamName string) string {
+	return strings.SplitN(ID(streamName), "+", 2)[0]
+}
+
+//IsCategory returns true if the name has no ID, and so names a whole category rather than a stream in it
+func IsCategory(streamName string) bool {
+	return !strings.Contains(streamName, "-")
+}
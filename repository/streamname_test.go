@@ -0,0 +1,48 @@
+package repository_test
+
+import (
+	"testing"
+
+	. "github.com/blackhatbrigade/gomessagestore/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamNameParts(t *testing.T) {
+	tests := []struct {
+		streamName         string
+		expectedCategory   string
+		expectedID         string
+		expectedCardinalID string
+		expectedIsCategory bool
+	}{{
+		streamName:         "account-123",
+		expectedCategory:   "account",
+		expectedID:         "123",
+		expectedCardinalID: "123",
+	}, {
+		streamName:         "account:command-123-456",
+		expectedCategory:   "account:command",
+		expectedID:         "123-456",
+		expectedCardinalID: "123-456",
+	}, {
+		streamName:         "account-123+456",
+		expectedCategory:   "account",
+		expectedID:         "123+456",
+		expectedCardinalID: "123",
+	}, {
+		streamName:         "account:command",
+		expectedCategory:   "account:command",
+		expectedIsCategory: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.streamName, func(t *testing.T) {
+			assert := assert.New(t)
+
+			assert.Equal(test.expectedCategory, Category(test.streamName))
+			assert.Equal(test.expectedID, ID(test.streamName))
+			assert.Equal(test.expectedCardinalID, CardinalID(test.streamName))
+			assert.Equal(test.expectedIsCategory, IsCategory(test.streamName))
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package gomessagestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+)
+
+const (
+	replyStreamNameKey  = "replyStreamName"    // the metadata field a command's reply stream is written to, like Eventide
+	causationMessageKey = "causationMessageId" // the metadata field on a reply pointing back at the command it answers
+)
+
+type requestOpts struct {
+	replyStream *string            // where replies are read from; defaults to <category>:reply-<command id>
+	timeout     time.Duration      // how long to wait for a reply before giving up
+	pollTime    time.Duration      // the time between reads of the reply stream
+	converters  []MessageConverter // convert non-command/event replies
+}
+
+// RequestOption provides optional arguments to the Request function
+type RequestOption func(r *requestOpts) error
+
+// Request writes a command with a reply stream in its metadata, then waits for the first message in the reply stream caused by that command.
+// ErrRequestTimedOut is returned if no reply shows up in time.
+func (ms *msgStore) Request(ctx context.Context, cmd Command, opts ...RequestOption) (Message, error) {
+	requestOptions := &requestOpts{
+		timeout:  30 * time.Second,
+		pollTime: 100 * time.Millisecond,
+	}
+	for _, option := range opts {
+		if err := option(requestOptions); err != nil {
+			return nil, err
+		}
+	}
+
+	replyStream := fmt.Sprintf("%s:reply-%s", cmd.StreamCategory, cmd.ID)
+	if requestOptions.replyStream != nil {
+		replyStream = *requestOptions.replyStream
+	}
+
+	metadata, err := withMetadataField(cmd.Metadata, replyStreamNameKey, replyStream)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Metadata = metadata
+
+	if err := ms.Write(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestOptions.timeout)
+	defer cancel()
+
+	getOptions := []GetOption{GenericStream(replyStream)}
+	for _, conv := range requestOptions.converters {
+		getOptions = append(getOptions, Converter(conv))
+	}
+
+	ticker := time.NewTicker(requestOptions.pollTime)
+	defer ticker.Stop()
+
+	var version int64
+	for {
+		msgs, err := ms.Get(ctx, append(getOptions, SinceVersion(version))...)
+		if err != nil && ctx.Err() == nil {
+			return nil, err
+		}
+
+		for _, msg := range msgs {
+			if causedBy(msg, cmd.ID) {
+				return msg, nil
+			}
+			version = msg.Version() + 1
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				ms.log.WithField("replyStream", replyStream).Error("Request: Timed out waiting for a reply")
+				return nil, ErrRequestTimedOut
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// NewReply creates an event answering a command sent with Request, written to the command's reply stream
+func NewReply(cmd Command, id uuid.UUID, msgType string, data []byte) (Event, error) {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(cmd.Metadata, &metadata); err != nil {
+		return Event{}, ErrMissingReplyStream
+	}
+
+	replyStream, _ := metadata[replyStreamNameKey].(string)
+	if replyStream == "" {
+		return Event{}, ErrMissingReplyStream
+	}
+
+	category, entityID, err := parseReplyStream(replyStream)
+	if err != nil {
+		return Event{}, err
+	}
+
+	replyMetadata, err := json.Marshal(map[string]interface{}{causationMessageKey: cmd.ID})
+	if err != nil {
+		return Event{}, err
+	}
+
+	return NewEvent(id, entityID, category, msgType, data, replyMetadata), nil
+}
+
+// parseReplyStream splits a reply stream into the category and entity ID a reply is written with
+func parseReplyStream(streamName string) (string, uuid.UUID, error) {
+	parts := strings.SplitN(streamName, "-", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", NilUUID, ErrInvalidReplyStream
+	}
+	entityID, err := uuid.Parse(parts[1])
+	if err != nil || entityID == NilUUID {
+		return "", NilUUID, ErrInvalidReplyStream
+	}
+
+	return parts[0], entityID, nil
+}
+
+// causedBy checks whether a message's metadata names the command as its cause
+func causedBy(msg Message, commandID uuid.UUID) bool {
+	envelope, err := msg.ToEnvelope()
+	if err != nil || envelope.Metadata == nil {
+		return false
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(envelope.Metadata, &metadata); err != nil {
+		return false
+	}
+
+	causation, _ := metadata[causationMessageKey].(string)
+	return strings.EqualFold(causation, commandID.String())
+}
+
+// withMetadataField sets a field in JSON metadata, which has to be an object if it's set at all
+func withMetadataField(metadata []byte, key string, value interface{}) ([]byte, error) {
+	fields := map[string]interface{}{}
+	if len(metadata) > 0 && string(metadata) != "null" {
+		if err := json.Unmarshal(metadata, &fields); err != nil {
+			return nil, ErrMetadataNotAnObject
+		}
+	}
+	fields[key] = value
+
+	return json.Marshal(fields)
+}
+
+// ReplyStream sets the entity stream Request reads replies from, instead of <category>:reply-<command id>
+func ReplyStream(streamName string) RequestOption {
+	return func(r *requestOpts) error {
+		if _, _, err := parseReplyStream(streamName); err != nil {
+			return err
+		}
+		r.replyStream = &streamName
+		return nil
+	}
+}
+
+// RequestTimeout sets how long Request waits for a reply
+func RequestTimeout(timeout time.Duration) RequestOption {
+	return func(r *requestOpts) error {
+		if timeout <= 0 {
+			return ErrInvalidRequestTimeout
+		}
+		r.timeout = timeout
+		return nil
+	}
+}
+
+// RequestPollTime sets the time between reads of the reply stream
+func RequestPollTime(pollTime time.Duration) RequestOption {
+	return func(r *requestOpts) error {
+		if pollTime <= 0 {
+			return ErrInvalidPollTime
+		}
+		r.pollTime = pollTime
+		return nil
+	}
+}
+
+// RequestConverter allows for automatic converting of non-Command/Event replies
+func RequestConverter(converter MessageConverter) RequestOption {
+	return func(r *requestOpts) error {
+		r.converters = append(r.converters, converter)
+		return nil
+	}
+}
@@ -0,0 +1,205 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/uuid"
+)
+
+// replyTo waits for the command to show up in its command stream, then answers it with an unrelated event and a reply
+func replyTo(ctx context.Context, msgStore MessageStore, category string, commandID uuid.UUID) error {
+	for {
+		msgs, err := msgStore.Get(ctx, CommandStream(category))
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range msgs {
+			cmd, ok := msg.(Command)
+			if !ok || cmd.ID != commandID {
+				continue
+			}
+
+			unrelated, err := NewReply(cmd, uuid2, "Unrelated", []byte(`{}`))
+			if err != nil {
+				return err
+			}
+			unrelated.Metadata = []byte(`{"causationMessageId":"` + uuid3.String() + `"}`)
+			if err := msgStore.Write(ctx, unrelated); err != nil {
+				return err
+			}
+
+			reply, err := NewReply(cmd, uuid4, "Done", []byte(`{"Field1":"done"}`))
+			if err != nil {
+				return err
+			}
+			return msgStore.Write(ctx, reply)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestRequestReturnsTheReply(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	cmd := NewCommand(uuid1, NilUUID, "thing", "DoThing", []byte(`{}`), []byte(`{"Field1":"b"}`))
+
+	replied := make(chan error, 1)
+	go func() {
+		replied <- replyTo(ctx, msgStore, "thing", uuid1)
+	}()
+
+	reply, err := msgStore.Request(ctx, cmd, RequestPollTime(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Request failed: %s", err)
+	}
+	if err := <-replied; err != nil {
+		t.Fatalf("Replying failed: %s", err)
+	}
+
+	evt, ok := reply.(Event)
+	if !ok {
+		t.Fatalf("Expected an Event reply, got %T", reply)
+	}
+	if evt.ID != uuid4 || evt.MessageType != "Done" {
+		t.Errorf("Got the wrong reply: %+v", evt)
+	}
+	if evt.StreamCategory != "thing:reply" || evt.EntityID != uuid1 {
+		t.Errorf("Reply was not in the default reply stream: %s-%s", evt.StreamCategory, evt.EntityID)
+	}
+
+	msgs, err := msgStore.Get(ctx, CommandStream("thing"))
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("Expected the command to be written, got %d messages and %v", len(msgs), err)
+	}
+	var metadata map[string]string
+	if err := json.Unmarshal(msgs[0].(Command).Metadata, &metadata); err != nil {
+		t.Fatalf("Command metadata is not JSON: %s", err)
+	}
+	if metadata["replyStreamName"] != "thing:reply-"+uuid1.String() || metadata["Field1"] != "b" {
+		t.Errorf("Command metadata did not keep its fields and gain a reply stream: %v", metadata)
+	}
+}
+
+func TestRequestWithReplyStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	cmd := NewCommand(uuid1, NilUUID, "thing", "DoThing", []byte(`{}`), nil)
+
+	replied := make(chan error, 1)
+	go func() {
+		replied <- replyTo(ctx, msgStore, "thing", uuid1)
+	}()
+
+	reply, err := msgStore.Request(ctx, cmd, ReplyStream("gateway-"+uuid5.String()), RequestPollTime(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Request failed: %s", err)
+	}
+	if err := <-replied; err != nil {
+		t.Fatalf("Replying failed: %s", err)
+	}
+
+	evt := reply.(Event)
+	if evt.StreamCategory != "gateway" || evt.EntityID != uuid5 || evt.ID != uuid4 {
+		t.Errorf("Got the wrong reply: %+v", evt)
+	}
+}
+
+func TestRequestErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		expectedError error
+		cancelled     bool
+		metadata      []byte
+		opts          []RequestOption
+	}{{
+		name:          "when no reply comes in time, the request times out",
+		expectedError: ErrRequestTimedOut,
+		opts:          []RequestOption{RequestTimeout(20 * time.Millisecond), RequestPollTime(time.Millisecond)},
+	}, {
+		name:          "when the context is cancelled, its error is returned",
+		expectedError: context.Canceled,
+		cancelled:     true,
+	}, {
+		name:          "when the command's metadata isn't a JSON object, an error is returned",
+		expectedError: ErrMetadataNotAnObject,
+		metadata:      []byte(`["not", "an", "object"]`),
+	}, {
+		name:          "the timeout must be positive",
+		expectedError: ErrInvalidRequestTimeout,
+		opts:          []RequestOption{RequestTimeout(0)},
+	}, {
+		name:          "the poll time must be positive",
+		expectedError: ErrInvalidPollTime,
+		opts:          []RequestOption{RequestPollTime(-time.Second)},
+	}, {
+		name:          "the reply stream must be an entity stream",
+		expectedError: ErrInvalidReplyStream,
+		opts:          []RequestOption{ReplyStream("gateway")},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			msgStore := NewMockMessageStoreWithMessages(nil)
+			cmd := NewCommand(uuid1, NilUUID, "thing", "DoThing", []byte(`{}`), test.metadata)
+
+			if test.cancelled {
+				go func() {
+					time.Sleep(10 * time.Millisecond)
+					cancel()
+				}()
+			}
+
+			_, err := msgStore.Request(ctx, cmd, test.opts...)
+			if err != test.expectedError {
+				t.Errorf("Failed to get expected error from Request\nExpected: %s\n and got: %s\n", test.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestNewReplyErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		expectedError error
+		metadata      []byte
+	}{{
+		name:          "when the command has no metadata, there is no reply stream",
+		expectedError: ErrMissingReplyStream,
+	}, {
+		name:          "when the command's metadata has no reply stream, an error is returned",
+		expectedError: ErrMissingReplyStream,
+		metadata:      []byte(`{"Field1":"b"}`),
+	}, {
+		name:          "when the reply stream isn't an entity stream, an error is returned",
+		expectedError: ErrInvalidReplyStream,
+		metadata:      []byte(`{"replyStreamName":"gateway:reply"}`),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := NewCommand(uuid1, NilUUID, "thing", "DoThing", []byte(`{}`), test.metadata)
+
+			_, err := NewReply(cmd, uuid2, "Done", []byte(`{}`))
+			if err != test.expectedError {
+				t.Errorf("Failed to get expected error from NewReply\nExpected: %s\n and got: %s\n", test.expectedError, err)
+			}
+		})
+	}
+}
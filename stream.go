@@ -0,0 +1,29 @@
+package gomessagestore
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StreamVersion returns the version of the last message in a stream, or -1 if the stream has no messages; cheaper than reading the last message
+func (ms *msgStore) StreamVersion(ctx context.Context, streamName string) (int64, error) {
+	version, err := ms.repo.GetStreamVersion(ctx, streamName)
+	if err != nil {
+		logrus.WithError(err).Error("StreamVersion: Error getting stream version")
+
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// StreamExists returns true if at least one message has been written to the stream
+func (ms *msgStore) StreamExists(ctx context.Context, streamName string) (bool, error) {
+	version, err := ms.StreamVersion(ctx, streamName)
+	if err != nil {
+		return false, err
+	}
+
+	return version >= 0, nil
+}
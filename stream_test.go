@@ -0,0 +1,98 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/repository/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+)
+
+func TestStreamVersion(t *testing.T) {
+	tests := []struct {
+		name            string
+		repoVersion     int64
+		repoErr         error
+		expectedVersion int64
+		expectedExists  bool
+		expectedErr     error
+	}{{
+		name:            "when the stream has messages, its version is returned and it exists",
+		repoVersion:     4,
+		expectedVersion: 4,
+		expectedExists:  true,
+	}, {
+		name:            "when the stream has one message, it exists",
+		repoVersion:     0,
+		expectedVersion: 0,
+		expectedExists:  true,
+	}, {
+		name:            "when the stream has no messages, the version is -1 and it doesn't exist",
+		repoVersion:     -1,
+		expectedVersion: -1,
+	}, {
+		name:        "when the repository fails, the error is returned",
+		repoErr:     errors.New("bad things with db happened"),
+		expectedErr: errors.New("bad things with db happened"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockRepository(ctrl)
+			ctx := context.Background()
+
+			mockRepo.
+				EXPECT().
+				GetStreamVersion(ctx, "account-1234").
+				Return(test.repoVersion, test.repoErr).
+				Times(2)
+
+			var logrusLogger = logrus.New()
+			logrusLogger.Out = ioutil.Discard
+			myMessageStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+
+			version, err := myMessageStore.StreamVersion(ctx, "account-1234")
+			if (err == nil) != (test.expectedErr == nil) || (err != nil && err.Error() != test.expectedErr.Error()) {
+				t.Errorf("Expected error %v and got %v", test.expectedErr, err)
+			}
+			if version != test.expectedVersion {
+				t.Errorf("Expected version %d and got %d", test.expectedVersion, version)
+			}
+
+			exists, err := myMessageStore.StreamExists(ctx, "account-1234")
+			if (err == nil) != (test.expectedErr == nil) {
+				t.Errorf("Expected error %v and got %v", test.expectedErr, err)
+			}
+			if exists != test.expectedExists {
+				t.Errorf("Expected exists to be %t and got %t", test.expectedExists, exists)
+			}
+		})
+	}
+}
+
+func TestStreamVersionOnMockMessageStore(t *testing.T) {
+	ctx := context.Background()
+	events := getSampleEvents()
+	myMessageStore := NewMockMessageStoreWithMessages(eventsToMessageSlice(events))
+
+	msgEnv, _ := events[len(events)-1].ToEnvelope()
+	version, err := myMessageStore.StreamVersion(ctx, msgEnv.StreamName)
+	if err != nil {
+		t.Errorf("Expected no error and got %v", err)
+	}
+	if version != events[len(events)-1].Version() {
+		t.Errorf("Expected version %d and got %d", events[len(events)-1].Version(), version)
+	}
+
+	exists, err := myMessageStore.StreamExists(ctx, "nothing-here")
+	if err != nil || exists {
+		t.Errorf("Expected a stream with no messages not to exist, got %t (%v)", exists, err)
+	}
+}
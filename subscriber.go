@@ -3,6 +3,7 @@ package gomessagestore
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -12,11 +13,16 @@ import (
 // Subscriber allows for reaching out to the message service on a continual basis
 type Subscriber interface {
 	Start(context.Context) error
+	ResetPosition(ctx context.Context, position int64) error // commits a position for the subscriber to start from
+	ResetToTime(ctx context.Context, t time.Time) error      // commits the position of the first message written at or after a time
+	ResetToBeginning(ctx context.Context) error              // commits the position of the first message
+	Replay(ctx context.Context, from, to int64) error        // handles the messages from one position to another, then returns
 }
 
 type subscriber struct {
 	config       *SubscriberConfig
 	poller       Poller
+	worker       SubscriptionWorker
 	ms           MessageStore
 	handlers     []MessageHandler
 	subscriberID string
@@ -35,16 +41,7 @@ func (ms *msgStore) CreateSubscriber(subscriberID string, handlers []MessageHand
 		return nil, err
 	}
 
-	worker, err := CreateWorker(
-		subscriber.ms,
-		subscriberID,
-		subscriber.handlers,
-		subscriber.config)
-	if err != nil {
-		return nil, err
-	}
-
-	subscriber.poller, err = CreatePoller(subscriber.ms, worker, subscriber.config)
+	subscriber.poller, err = CreatePoller(subscriber.ms, subscriber.worker, subscriber.config)
 	if err != nil {
 		return nil, err
 	}
@@ -113,5 +110,14 @@ func createSubscriberWithPoller(ms MessageStore, subscriberID string, handlers [
 			"subscriberID": subscriber.subscriberID,
 		})
 
+	subscriber.worker, err = CreateWorker(
+		subscriber.ms,
+		subscriberID,
+		subscriber.handlers,
+		subscriber.config)
+	if err != nil {
+		return nil, err
+	}
+
 	return subscriber, nil
 }
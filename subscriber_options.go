@@ -1,8 +1,10 @@
 package gomessagestore
 
 import (
+	"strings"
 	"time"
 
+	"github.com/blackhatbrigade/gomessagestore/repository"
 	"github.com/blackhatbrigade/gomessagestore/uuid"
 	"github.com/sirupsen/logrus"
 )
@@ -16,11 +18,15 @@ type SubscriberConfig struct {
 	stream          bool
 	category        string
 	commandCategory string
-	pollTime        time.Duration // the time interval between polling operations
-	pollErrorDelay  time.Duration // the time interval to wait after an error occurs during a poll operation
-	updateInterval  int           //
-	batchSize       int           // the maximum amount of messages to be retrieved at a time
-	position        int64         // the position from which to retrieve messages
+	categories      []string                  // every category subscribed to; more than one are read together in global position order
+	all             bool                      // subscribed to every category, in global position order
+	categoryFilter  repository.CategoryFilter // the categories to include or exclude when subscribed to every category
+	pollTime        time.Duration             // the time interval between polling operations
+	pollErrorDelay  time.Duration             // the time interval to wait after an error occurs during a poll operation
+	updateInterval  int                       //
+	batchSize       int                       // the maximum amount of messages to be retrieved at a time
+	position        int64                     // the position from which to retrieve messages
+	sinceTime       *time.Time                // where to start when no position has been committed yet
 	log             logrus.FieldLogger
 	converters      []MessageConverter // convert non-command/event messages
 	errorFunc       func(error)
@@ -58,30 +64,84 @@ func SubscribeToCommandStream(category string) SubscriberOption {
 	}
 }
 
-//SubscribeToCommandCategory subscribes to a category of streams and ensures that it is not also subscribed to a stream
+//SubscribeToCommandCategory subscribes to a category of command streams and ensures that it is not also subscribed to a stream.
+//It can be combined with other categories, which are all handled in global position order
 func SubscribeToCommandCategory(category string) SubscriberOption {
 	return func(sub *SubscriberConfig) error {
 		if sub.stream {
 			return ErrSubscriberCannotUseBothStreamAndCategory
 		}
-		if sub.category != "" {
-			return ErrSubscriberCannotSubscribeToMultipleCategories
-		}
-		sub.category = category + ":command"
-		return nil
+		return sub.addCategory(category + ":command")
 	}
 }
 
-//SubscribeToCategory subscribes to a category of streams and ensures that it is not also subscribed to a stream
+//SubscribeToCategory subscribes to a category of streams and ensures that it is not also subscribed to a stream.
+//It can be called more than once to follow several categories, which are all handled in global position order with a single position
 func SubscribeToCategory(category string) SubscriberOption {
 	return func(sub *SubscriberConfig) error {
 		if sub.stream {
 			return ErrSubscriberCannotUseBothStreamAndCategory
 		}
-		if sub.category != "" {
-			return ErrSubscriberCannotSubscribeToMultipleCategories
+		if category == "" {
+			return nil // left for GetSubscriberConfig to report
+		}
+		return sub.addCategory(category)
+	}
+}
+
+// addCategory adds a category to those subscribed to, refusing the same category twice
+func (sub *SubscriberConfig) addCategory(category string) error {
+	for _, existing := range sub.categories {
+		if existing == category {
+			return ErrSubscriberCategorySubscribedTwice
 		}
+	}
+	if sub.category == "" {
 		sub.category = category
+	}
+	sub.categories = append(sub.categories, category)
+	return nil
+}
+
+//SubscribeToAll subscribes to every message in the message store, in global position order, and can't be combined with a stream or category
+func SubscribeToAll() SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		sub.all = true
+		return nil
+	}
+}
+
+//SubscribeIncludingCategories limits SubscribeToAll to messages in the categories given
+func SubscribeIncludingCategories(categories ...string) SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		for _, category := range categories {
+			if strings.Contains(category, "-") {
+				return ErrInvalidMessageCategory
+			}
+		}
+		sub.categoryFilter.Include = append(sub.categoryFilter.Include, categories...)
+		return nil
+	}
+}
+
+//SubscribeExcludingCategories stops SubscribeToAll from handling messages in the categories given
+func SubscribeExcludingCategories(categories ...string) SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		for _, category := range categories {
+			if strings.Contains(category, "-") {
+				return ErrInvalidMessageCategory
+			}
+		}
+		sub.categoryFilter.Exclude = append(sub.categoryFilter.Exclude, categories...)
+		return nil
+	}
+}
+
+//SubscribeSinceTime starts a subscriber that hasn't committed a position yet from the first message written at or after a time,
+//instead of from the beginning
+func SubscribeSinceTime(t time.Time) SubscriberOption {
+	return func(sub *SubscriberConfig) error {
+		sub.sinceTime = &t
 		return nil
 	}
 }
@@ -138,7 +198,13 @@ func GetSubscriberConfig(opts ...SubscriberOption) (*SubscriberConfig, error) {
 		}
 	}
 
-	if !config.stream && config.category == "" {
+	if config.all && (config.stream || config.category != "") {
+		return nil, ErrSubscriberCannotUseAllWithStreamOrCategory
+	}
+	if !config.all && (len(config.categoryFilter.Include) > 0 || len(config.categoryFilter.Exclude) > 0) {
+		return nil, ErrCategoryFiltersRequireAll
+	}
+	if !config.all && !config.stream && config.category == "" {
 		return nil, ErrSubscriberNeedsCategoryOrStream
 	}
 	if config.pollTime <= 0 {
@@ -0,0 +1,124 @@
+package gomessagestore
+
+import (
+	"context"
+	"time"
+)
+
+// ResetPosition commits a position for the subscriber, which it starts handling messages from the next time it starts.
+// For stream subscriptions the position is a version in the stream, otherwise it's a global position.
+func (sub *subscriber) ResetPosition(ctx context.Context, position int64) error {
+	if position < 0 {
+		return ErrInvalidSubscriberPosition
+	}
+
+	sub.config.log.WithField("position", position).Info("Resetting subscriber position")
+	return sub.worker.SetPosition(ctx, position)
+}
+
+// ResetToBeginning commits a position before every message subscribed to, so they're all handled again the next time the subscriber starts
+func (sub *subscriber) ResetToBeginning(ctx context.Context) error {
+	return sub.ResetPosition(ctx, 0)
+}
+
+// ResetToTime commits the position of the first message subscribed to that was written at or after a time.
+// When there's no such message, the position after the last message is committed.
+func (sub *subscriber) ResetToTime(ctx context.Context, t time.Time) error {
+	position, err := sub.ms.PositionAtTime(ctx, t, sub.config.subscribedTo()...)
+	if err != nil {
+		return err
+	}
+
+	return sub.ResetPosition(ctx, position)
+}
+
+// Replay handles the messages subscribed to from one position to another, both included, then returns.
+// It's the subscriber run by its poller as usual, so the subscriber's position is committed as it goes and left just after the last
+// message replayed; starting the subscriber afterwards carries on from there. A subscriber that was already past the end of the
+// replay keeps its position, so nothing it has handled since is handled again.
+func (sub *subscriber) Replay(ctx context.Context, from, to int64) error {
+	if from < 0 || to < from {
+		return ErrInvalidReplayRange
+	}
+
+	current, err := sub.worker.GetPosition(ctx)
+	if err != nil {
+		return err
+	}
+
+	worker := &replayWorker{
+		SubscriptionWorker: sub.worker,
+		config:             sub.config,
+		to:                 to,
+		committed:          current,
+	}
+	pol := &poller{
+		config:   sub.config,
+		ms:       sub.ms,
+		worker:   worker,
+		position: from,
+	}
+
+	sub.config.log.WithField("from", from).WithField("to", to).Info("Replaying messages")
+	for {
+		if err := pol.Poll(ctx); err != nil {
+			return err
+		}
+		if worker.last < 0 {
+			break // nothing left to replay
+		}
+		if pol.position <= worker.last {
+			pol.position = worker.last + 1 // the rest of the batch has no handlers
+		}
+	}
+	if worker.passedTo && pol.position <= to {
+		pol.position = to + 1 // only handled types are read, so whatever is left up to the end of the replay has no handlers
+	}
+
+	return worker.SetPosition(ctx, pol.position)
+}
+
+// replayWorker stops a subscription worker's messages at the end of a replay
+type replayWorker struct {
+	SubscriptionWorker
+	config    *SubscriberConfig
+	to        int64 // the position of the last message to replay
+	last      int64 // the position of the last message read, or -1 when the last read found nothing to replay
+	passedTo  bool  // set once a read finds messages after the end of the replay
+	committed int64 // the furthest position committed, which the replay never moves the subscriber back from
+}
+
+func (rw *replayWorker) GetMessages(ctx context.Context, position int64) ([]Message, error) {
+	msgs, err := rw.SubscriptionWorker.GetMessages(ctx, position)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, msg := range msgs {
+		if rw.config.positionOf(msg) > rw.to {
+			rw.passedTo = true
+			msgs = msgs[:i]
+			break
+		}
+	}
+
+	rw.last = -1
+	if len(msgs) > 0 {
+		rw.last = rw.config.positionOf(msgs[len(msgs)-1])
+	}
+
+	return msgs, nil
+}
+
+// SetPosition commits a position only when it moves the subscriber forward
+func (rw *replayWorker) SetPosition(ctx context.Context, position int64) error {
+	if position <= rw.committed {
+		return nil
+	}
+
+	if err := rw.SubscriptionWorker.SetPosition(ctx, position); err != nil {
+		return err
+	}
+	rw.committed = position
+	return nil
+}
@@ -0,0 +1,178 @@
+package gomessagestore_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/blackhatbrigade/gomessagestore"
+)
+
+// positionRecorder records the global positions of the messages it handles
+type positionRecorder struct {
+	class     string
+	mutex     sync.Mutex
+	positions []int64
+}
+
+func (pr *positionRecorder) Type() string {
+	return pr.class
+}
+
+func (pr *positionRecorder) Process(ctx context.Context, msg Message) error {
+	pr.mutex.Lock()
+	defer pr.mutex.Unlock()
+	pr.positions = append(pr.positions, msg.Position())
+	return nil
+}
+
+// writeAccountEvents writes an event of each type given to the account category, one after another
+func writeAccountEvents(msgStore MessageStore, types ...string) {
+	for _, msgType := range types {
+		panicIf(msgStore.Write(context.Background(), NewEvent(NewID(), uuid1, "account", msgType, []byte(`{}`), nil)))
+	}
+}
+
+// committedPosition reads the position a subscriber with the config would start from
+func committedPosition(t *testing.T, msgStore MessageStore, subscriberID string, config *SubscriberConfig) int64 {
+	worker, err := CreateWorker(msgStore, subscriberID, nil, config)
+	panicIf(err)
+	position, err := worker.GetPosition(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get position: %s", err)
+	}
+
+	return position
+}
+
+func TestSubscriberResetPosition(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	writeAccountEvents(msgStore, "Opened", "Opened", "Opened")
+
+	subscriber, err := msgStore.CreateSubscriber("accountProjector", []MessageHandler{&positionRecorder{class: "Opened"}}, SubscribeToCategory("account"))
+	panicIf(err)
+
+	panicIf(subscriber.ResetPosition(ctx, 2))
+	if position := committedPosition(t, msgStore, "accountProjector", nil); position != 2 {
+		t.Errorf("Expected position 2, got %d", position)
+	}
+
+	panicIf(subscriber.ResetToBeginning(ctx))
+	if position := committedPosition(t, msgStore, "accountProjector", nil); position != 0 {
+		t.Errorf("Expected position 0, got %d", position)
+	}
+
+	if err := subscriber.ResetPosition(ctx, -1); err != ErrInvalidSubscriberPosition {
+		t.Errorf("Failed to get expected error from ResetPosition\nExpected: %s\n and got: %s\n", ErrInvalidSubscriberPosition, err)
+	}
+}
+
+func TestSubscriberResetToTime(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	writeAccountEvents(msgStore, "Opened", "Opened")
+	time.Sleep(5 * time.Millisecond)
+	since := time.Now()
+	writeAccountEvents(msgStore, "Deposited", "Deposited")
+
+	subscriber, err := msgStore.CreateSubscriber("accountProjector", []MessageHandler{&positionRecorder{class: "Opened"}}, SubscribeToCategory("account"))
+	panicIf(err)
+
+	panicIf(subscriber.ResetToTime(ctx, since))
+	if position := committedPosition(t, msgStore, "accountProjector", nil); position != 3 {
+		t.Errorf("Expected the position of the first message written since, 3, got %d", position)
+	}
+
+	panicIf(subscriber.ResetToTime(ctx, time.Now().Add(time.Hour)))
+	if position := committedPosition(t, msgStore, "accountProjector", nil); position != 6 {
+		t.Errorf("Expected the next global position, 6, as the first reset was written at 5, got %d", position)
+	}
+}
+
+func TestSubscriberPositionFromOutside(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMockMessageStoreWithMessages(nil)
+
+	position, err := GetSubscriberPosition(ctx, msgStore, "accountProjector")
+	panicIf(err)
+	if position != 0 {
+		t.Errorf("Expected a subscriber without a position to be at 0, got %d", position)
+	}
+
+	panicIf(SetSubscriberPosition(ctx, msgStore, "accountProjector", 7))
+	position, err = GetSubscriberPosition(ctx, msgStore, "accountProjector")
+	panicIf(err)
+	if position != 7 {
+		t.Errorf("Expected the position set, 7, got %d", position)
+	}
+	if position := committedPosition(t, msgStore, "accountProjector", nil); position != 7 {
+		t.Errorf("Expected the subscriber to see the position set, 7, got %d", position)
+	}
+}
+
+func TestSubscriberReplay(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	writeAccountEvents(msgStore, "Opened", "Opened", "Closed", "Opened", "Closed", "Opened")
+
+	recorder := &positionRecorder{class: "Opened"}
+	subscriber, err := msgStore.CreateSubscriber("accountProjector", []MessageHandler{recorder}, SubscribeToCategory("account"))
+	panicIf(err)
+
+	panicIf(subscriber.Replay(ctx, 2, 5))
+
+	if len(recorder.positions) != 2 || recorder.positions[0] != 2 || recorder.positions[1] != 4 {
+		t.Errorf("Expected the messages at 2 and 4 to be replayed, got %v", recorder.positions)
+	}
+	if position := committedPosition(t, msgStore, "accountProjector", nil); position != 6 {
+		t.Errorf("Expected the position after the replay, 6, got %d", position)
+	}
+
+	if err := subscriber.Replay(ctx, 5, 2); err != ErrInvalidReplayRange {
+		t.Errorf("Failed to get expected error from Replay\nExpected: %s\n and got: %s\n", ErrInvalidReplayRange, err)
+	}
+}
+
+func TestSubscriberReplayDoesNotMovePositionBack(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	writeAccountEvents(msgStore, "Opened", "Opened", "Closed", "Opened", "Closed", "Opened")
+
+	recorder := &positionRecorder{class: "Opened"}
+	subscriber, err := msgStore.CreateSubscriber("accountProjector", []MessageHandler{recorder}, SubscribeToCategory("account"), SubscribeBatchSize(1), UpdatePositionEvery(2))
+	panicIf(err)
+	panicIf(subscriber.ResetPosition(ctx, 6))
+
+	panicIf(subscriber.Replay(ctx, 1, 4))
+
+	if len(recorder.positions) != 3 {
+		t.Errorf("Expected the messages at 1, 2 and 4 to be replayed, got %v", recorder.positions)
+	}
+	if position := committedPosition(t, msgStore, "accountProjector", nil); position != 6 {
+		t.Errorf("Expected the position from before the replay, 6, got %d", position)
+	}
+}
+
+func TestSubscriberStartsSinceTime(t *testing.T) {
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	writeAccountEvents(msgStore, "Opened", "Opened")
+	time.Sleep(5 * time.Millisecond)
+	since := time.Now()
+	writeAccountEvents(msgStore, "Deposited")
+
+	config, err := GetSubscriberConfig(SubscribeToCategory("account"), SubscribeSinceTime(since))
+	panicIf(err)
+	if position := committedPosition(t, msgStore, "accountProjector", config); position != 3 {
+		t.Errorf("Expected a subscriber without a position to start at the first message written since, 3, got %d", position)
+	}
+
+	// once a position is committed, it's used instead
+	subscriber, err := msgStore.CreateSubscriber("accountProjector", []MessageHandler{&positionRecorder{class: "Opened"}}, SubscribeToCategory("account"))
+	panicIf(err)
+	panicIf(subscriber.ResetToBeginning(context.Background()))
+	if position := committedPosition(t, msgStore, "accountProjector", config); position != 0 {
+		t.Errorf("Expected the committed position, 0, got %d", position)
+	}
+}
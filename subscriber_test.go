@@ -138,26 +138,73 @@ func TestCreateSubscriberOptions(t *testing.T) {
 			SubscribeToEntityStream("some category", uuid1),
 		},
 	}, {
-		name:          "Subscribe should only accept one category subscription request, (entity and entity)",
-		expectedError: ErrSubscriberCannotSubscribeToMultipleCategories,
+		name:          "Subscribe should not accept the same category twice, (entity and entity)",
+		expectedError: ErrSubscriberCategorySubscribedTwice,
 		opts: []SubscriberOption{
 			SubscribeToCategory("some entity category"),
 			SubscribeToCategory("some entity category"),
 		},
 	}, {
-		name:          "Subscribe should only accept one category subscription request, (command and entity)",
-		expectedError: ErrSubscriberCannotSubscribeToMultipleCategories,
+		name: "Subscribe accepts several categories, (command and entity)",
 		opts: []SubscriberOption{
 			SubscribeToCommandCategory("some command category"),
 			SubscribeToCategory("some entity category"),
 		},
 	}, {
-		name:          "Subscribe should only accept one category subscription request, (command and command)",
-		expectedError: ErrSubscriberCannotSubscribeToMultipleCategories,
+		name: "Subscribe accepts several categories, (entity and entity)",
+		opts: []SubscriberOption{
+			SubscribeToCategory("some entity category"),
+			SubscribeToCategory("some other entity category"),
+		},
+	}, {
+		name:          "Subscribe should not accept the same category twice, (command and command)",
+		expectedError: ErrSubscriberCategorySubscribedTwice,
 		opts: []SubscriberOption{
 			SubscribeToCommandCategory("some command category"),
 			SubscribeToCommandCategory("some command category"),
 		},
+	}, {
+		name:          "Subscribe to several categories cannot also subscribe to a stream",
+		expectedError: ErrSubscriberCannotUseBothStreamAndCategory,
+		opts: []SubscriberOption{
+			SubscribeToCategory("some entity category"),
+			SubscribeToCategory("some other entity category"),
+			SubscribeToCommandStream("some category"),
+		},
+	}, {
+		name: "Subscribe to all does not return error",
+		opts: []SubscriberOption{
+			SubscribeToAll(),
+			SubscribeExcludingCategories("audit"),
+		},
+	}, {
+		name:          "Subscribe to all cannot also subscribe to a category",
+		expectedError: ErrSubscriberCannotUseAllWithStreamOrCategory,
+		opts: []SubscriberOption{
+			SubscribeToAll(),
+			SubscribeToCategory("some category"),
+		},
+	}, {
+		name:          "Subscribe to all cannot also subscribe to a stream",
+		expectedError: ErrSubscriberCannotUseAllWithStreamOrCategory,
+		opts: []SubscriberOption{
+			SubscribeToEntityStream("some category", uuid1),
+			SubscribeToAll(),
+		},
+	}, {
+		name:          "Including categories requires subscribing to all",
+		expectedError: ErrCategoryFiltersRequireAll,
+		opts: []SubscriberOption{
+			SubscribeToCategory("some category"),
+			SubscribeIncludingCategories("some category"),
+		},
+	}, {
+		name:          "Excluded categories cannot be streams",
+		expectedError: ErrInvalidMessageCategory,
+		opts: []SubscriberOption{
+			SubscribeToAll(),
+			SubscribeExcludingCategories("some-stream"),
+		},
 	}, {
 		name:          "Cannot set 0 poll time",
 		expectedError: ErrInvalidPollTime,
@@ -300,6 +347,7 @@ func TestOnError(t *testing.T) {
 			"some category",
 			int64(0),
 			1000,
+			"type-",
 		).Return(messageEnvelopes, nil)
 
 	// act
@@ -10,21 +10,53 @@ func (sw *subscriptionWorker) GetMessages(ctx context.Context, position int64) (
 	for _, conv := range sw.config.converters {
 		opts = append(opts, Converter(conv))
 	}
-	if !sw.config.stream { // for stream subscription
+	if !sw.config.stream { // categories and every category are read by global position
 		opts = append(opts, SincePosition(position))
-		if sw.config.commandCategory != "" { // for commands
-			opts = append(opts, CommandCategory(sw.config.commandCategory))
+	} else { // streams are read by version
+		opts = append(opts, SinceVersion(position))
+	}
+
+	opts = append(opts, Types(sw.handledTypes()...)) // every message read is handled, so the position moves past the ones that aren't
+
+	return sw.ms.Get(ctx, append(opts, sw.config.subscribedTo()...)...)
+}
+
+// handledTypes gets the message types the worker has handlers for
+func (sw *subscriptionWorker) handledTypes() []string {
+	types := make([]string, 0, len(sw.handlers))
+	for _, handler := range sw.handlers {
+		types = append(types, handler.Type())
+	}
+
+	return types
+}
+
+// subscribedTo gets the options that pick what the subscriber reads
+func (sub *SubscriberConfig) subscribedTo() []GetOption {
+	opts := []GetOption{}
+	if sub.all { // for subscriptions to every category
+		opts = append(opts, All())
+		if len(sub.categoryFilter.Include) > 0 {
+			opts = append(opts, IncludeCategories(sub.categoryFilter.Include...))
+		}
+		if len(sub.categoryFilter.Exclude) > 0 {
+			opts = append(opts, ExcludeCategories(sub.categoryFilter.Exclude...))
+		}
+	} else if len(sub.categories) > 1 { // for subscriptions to several categories, merged by global position
+		opts = append(opts, All(), IncludeCategories(sub.categories...))
+	} else if !sub.stream { // for category subscription
+		if sub.commandCategory != "" { // for commands
+			opts = append(opts, CommandCategory(sub.commandCategory))
 		} else { // for events
-			opts = append(opts, Category(sw.config.category))
+			opts = append(opts, Category(sub.category))
 		}
-	} else { // for category subscription
-		opts = append(opts, SinceVersion(position))
-		if sw.config.commandCategory != "" { // for commands
-			opts = append(opts, CommandStream(sw.config.commandCategory))
+	} else { // for stream subscription
+		if sub.commandCategory != "" { // for commands
+			opts = append(opts, CommandStream(sub.commandCategory))
 		} else { // for events
-			opts = append(opts, EventStream(sw.config.category, sw.config.entityID))
+			opts = append(opts, EventStream(sub.category, sub.entityID))
 		}
 	}
 
-	return sw.ms.Get(ctx, opts...)
+	return opts
 }
@@ -16,7 +16,7 @@ import (
 var potato = errors.New("I'm a potato")
 
 func TestSubscriberGetsMessages(t *testing.T) {
-	messageHandler := &msgHandler{}
+	messageHandler := &msgHandler{class: "Happened"}
 	calledConverter := false
 
 	tests := []struct {
@@ -26,6 +26,8 @@ func TestSubscriberGetsMessages(t *testing.T) {
 		expectedPosition    int64
 		expectedStream      string
 		expectedCategory    string
+		expectedAll         bool
+		expectedFilter      repository.CategoryFilter
 		opts                []SubscriberOption
 		messageEnvelopes    []*repository.MessageEnvelope
 		repoReturnError     error
@@ -54,6 +56,28 @@ func TestSubscriberGetsMessages(t *testing.T) {
 		opts: []SubscriberOption{
 			SubscribeToCommandCategory("some category"),
 		},
+	}, {
+		name:             "When subscriber is called with SubscribeToAll() option, repository is called correctly",
+		expectedAll:      true,
+		expectedFilter:   repository.CategoryFilter{Include: []string{"some category"}, Exclude: []string{"audit"}},
+		handlers:         []MessageHandler{messageHandler},
+		expectedPosition: 5,
+		opts: []SubscriberOption{
+			SubscribeToAll(),
+			SubscribeIncludingCategories("some category"),
+			SubscribeExcludingCategories("audit"),
+		},
+	}, {
+		name:             "When subscriber is called with SubscribeToCategory() more than once, repository is called correctly",
+		expectedAll:      true,
+		expectedFilter:   repository.CategoryFilter{Include: []string{"some category", "other category", "some category:command"}},
+		handlers:         []MessageHandler{messageHandler},
+		expectedPosition: 5,
+		opts: []SubscriberOption{
+			SubscribeToCategory("some category"),
+			SubscribeToCategory("other category"),
+			SubscribeToCommandCategory("some category"),
+		},
 	}, {
 		name:           "When subscriber is called with SubscribeToEntityStream() option, repository is called correctly",
 		handlers:       []MessageHandler{messageHandler},
@@ -61,6 +85,14 @@ func TestSubscriberGetsMessages(t *testing.T) {
 		opts: []SubscriberOption{
 			SubscribeToCommandStream("some category"),
 		},
+	}, {
+		name:             "When subscriber has handlers for several types, only those types are read",
+		expectedCategory: "some category",
+		handlers:         []MessageHandler{messageHandler, &msgHandler{class: "AlsoHappened"}},
+		expectedPosition: 5,
+		opts: []SubscriberOption{
+			SubscribeToCategory("some category"),
+		},
 	}, {
 		name:           "When subscriber is called with WithConverter() option, repository is called correctly",
 		handlers:       []MessageHandler{messageHandler},
@@ -103,17 +135,27 @@ func TestSubscriberGetsMessages(t *testing.T) {
 
 			ctx := context.Background()
 			mockRepo := mock_repository.NewMockRepository(ctrl)
+			handledTypes := []interface{}{}
+			for _, handler := range test.handlers {
+				handledTypes = append(handledTypes, handler.Type())
+			}
 
 			if test.expectedStream != "" {
 				mockRepo.
 					EXPECT().
-					GetAllMessagesInStreamSince(ctx, test.expectedStream, test.expectedPosition, 1000).
+					GetAllMessagesInStreamSince(ctx, test.expectedStream, test.expectedPosition, 1000, handledTypes...).
 					Return(test.messageEnvelopes, test.repoReturnError)
 			}
 			if test.expectedCategory != "" {
 				mockRepo.
 					EXPECT().
-					GetAllMessagesInCategorySince(ctx, test.expectedCategory, test.expectedPosition, 1000).
+					GetAllMessagesInCategorySince(ctx, test.expectedCategory, test.expectedPosition, 1000, handledTypes...).
+					Return(test.messageEnvelopes, test.repoReturnError)
+			}
+			if test.expectedAll {
+				mockRepo.
+					EXPECT().
+					GetAllMessagesSince(ctx, test.expectedPosition, 1000, test.expectedFilter, handledTypes...).
 					Return(test.messageEnvelopes, test.repoReturnError)
 			}
 
@@ -157,3 +199,26 @@ func testConverter(called *bool) MessageConverter {
 		return nil, conversionError
 	}
 }
+
+func TestSubscriberOnlyReadsHandledTypes(t *testing.T) {
+	ctx := context.Background()
+	msgStore := NewMockMessageStoreWithMessages(nil)
+	writeAccountEvents(msgStore, "Opened", "Closed", "Closed", "Opened", "Closed")
+
+	recorder := &positionRecorder{class: "Opened"}
+	config, err := GetSubscriberConfig(SubscribeToCategory("account"), UpdatePositionEvery(2))
+	panicIf(err)
+	worker, err := CreateWorker(msgStore, "accountProjector", []MessageHandler{recorder}, config)
+	panicIf(err)
+	poller, err := CreatePoller(msgStore, worker, config)
+	panicIf(err)
+
+	panicIf(poller.Poll(ctx))
+
+	if len(recorder.positions) != 2 || recorder.positions[0] != 1 || recorder.positions[1] != 4 {
+		t.Errorf("Expected only the messages at 1 and 4 to be handled, got %v", recorder.positions)
+	}
+	if position := committedPosition(t, msgStore, "accountProjector", config); position != 5 {
+		t.Errorf("Expected the position after the last handled message, 5, got %d", position)
+	}
+}
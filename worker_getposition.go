@@ -13,30 +13,55 @@ import (
 
 // GetPosition retrieves the current position that messages should be retrieved from; first process of the polling loop
 func (sw *subscriptionWorker) GetPosition(ctx context.Context) (int64, error) {
+	position, found, err := readPosition(ctx, sw.ms, sw.subscriberID)
+	if err != nil || found {
+		return position, err
+	}
+
 	log := logrus.
 		WithFields(logrus.Fields{
 			"SubscriberID": sw.subscriberID,
 		})
+	if sw.config.sinceTime != nil {
+		log.Debug("no messages found for subscriber, starting from its start time")
+		return sw.ms.PositionAtTime(ctx, *sw.config.sinceTime, sw.config.subscribedTo()...)
+	}
+	log.Debug("no messages found for subscriber, using default")
+	return 0, nil
+}
+
+// GetSubscriberPosition reads the position a subscriber last committed, or 0 when it hasn't committed one, without creating the
+// subscriber; for tools that look at subscribers from outside
+func GetSubscriberPosition(ctx context.Context, ms MessageStore, subscriberID string) (int64, error) {
+	position, _, err := readPosition(ctx, ms, subscriberID)
+	return position, err
+}
+
+// readPosition reads the position a subscriber last committed, and whether it has committed one
+func readPosition(ctx context.Context, ms MessageStore, subscriberID string) (int64, bool, error) {
+	log := logrus.
+		WithFields(logrus.Fields{
+			"SubscriberID": subscriberID,
+		})
 
-	msgs, _ := sw.ms.Get(
+	msgs, _ := ms.Get(
 		ctx,
-		PositionStream(sw.subscriberID),
+		PositionStream(subscriberID),
 		Converter(convertEnvelopeToPositionMessage),
 		Last(),
 	)
 	if len(msgs) < 1 {
-		log.Debug("no messages found for subscriber, using default")
-		return 0, nil
+		return 0, false, nil
 	}
 
 	switch pos := msgs[0].(type) {
 	case *positionMessage:
-		return pos.MyPosition, nil
+		return pos.MyPosition, true, nil
 	default:
 		log.
 			WithError(ErrIncorrectMessageInPositionStream).
 			Error("incorrect message type in position stream")
-		return 0, nil
+		return 0, true, nil
 	}
 }
 
@@ -16,16 +16,21 @@ func (sw *subscriptionWorker) ProcessMessages(ctx context.Context, msgs []Messag
 					return
 				}
 
-				if !sw.config.stream {
-					// category subscriptions care about position
-					positionOfLastHandled = msg.Position()
-				} else {
-					// stream subscriptions care about version
-					positionOfLastHandled = msg.Version()
-				}
+				positionOfLastHandled = sw.config.positionOf(msg)
 				messagesHandled++
 			}
 		}
 	}
 	return
 }
+
+// positionOf is where a message sits in what's subscribed to
+func (sub *SubscriberConfig) positionOf(msg Message) int64 {
+	if !sub.stream {
+		// category subscriptions care about position
+		return msg.Position()
+	}
+
+	// stream subscriptions care about version
+	return msg.Version()
+}
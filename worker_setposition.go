@@ -8,6 +8,11 @@ import (
 
 //SetPosition sets the position of a subscriber; fourth process in the polling loop after all messages have been handled
 func (sw *subscriptionWorker) SetPosition(ctx context.Context, position int64) error {
+	return SetSubscriberPosition(ctx, sw.ms, sw.subscriberID, position)
+}
+
+//SetSubscriberPosition commits a position for a subscriber without creating the subscriber; for tools that move subscribers from outside
+func SetSubscriberPosition(ctx context.Context, ms MessageStore, subscriberID string, position int64) error {
 	newUUID := uuid.NewRandom()
 
 	var posMsg Message
@@ -15,10 +20,10 @@ func (sw *subscriptionWorker) SetPosition(ctx context.Context, position int64) e
 	posMsg = &positionMessage{
 		ID:           newUUID,
 		MyPosition:   position,
-		SubscriberID: sw.subscriberID,
+		SubscriberID: subscriberID,
 	}
 
-	return sw.ms.Write(
+	return ms.Write(
 		ctx,
 		posMsg,
 	)
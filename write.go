@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+
+	"github.com/blackhatbrigade/gomessagestore/repository"
 )
 
 type writer struct {
@@ -39,13 +41,24 @@ func (ms *msgStore) Write(ctx context.Context, message Message, opts ...WriteOpt
 	if writeOptions.atPosition != nil {
 		err = ms.repo.WriteMessageWithExpectedPosition(ctx, envelope, *writeOptions.atPosition)
 		if err != nil {
-			if matched, _ := regexp.Match(errMsg, []byte(err.Error())); matched {
+			if err == repository.ErrExpectedVersionFailed {
+				err = ErrExpectedVersionFailed
+			} else if matched, _ := regexp.Match(errMsg, []byte(err.Error())); matched {
 				err = ErrExpectedVersionFailed
 			}
 		}
 	} else {
 		err = ms.repo.WriteMessage(ctx, envelope)
 	}
+	if err == repository.ErrDuplicateMessageID {
+		// writing a message again is how idempotent writers (like process managers resending commands) find out it's already there
+		ms.
+			log.
+			WithField("id", envelope.ID).
+			Info("Write: Message already written")
+
+		return ErrDuplicateMessageID
+	}
 	if err != nil {
 
 		ms.
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	. "github.com/blackhatbrigade/gomessagestore"
+	"github.com/blackhatbrigade/gomessagestore/repository"
 	"github.com/blackhatbrigade/gomessagestore/repository/mocks"
 	"github.com/golang/mock/gomock"
 	"github.com/sirupsen/logrus"
@@ -56,6 +57,69 @@ func TestWriteWithAtPosition(t *testing.T) {
 	myMessageStore.Write(ctx, msg, AtPosition(42))
 }
 
+func TestWriteWithAtPositionMapsExpectedVersionErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	msg := getSampleCommand()
+	ctx := context.Background()
+
+	msgEnv := getSampleCommandAsEnvelopeEntityIDMissing()
+
+	mockRepo.
+		EXPECT().
+		WriteMessageWithExpectedPosition(ctx, msgEnv, int64(42)).
+		Return(repository.ErrExpectedVersionFailed)
+
+	var logrusLogger = logrus.New()
+	logrusLogger.Out = ioutil.Discard
+	myMessageStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+	err := myMessageStore.Write(ctx, msg, AtPosition(42))
+
+	if err != ErrExpectedVersionFailed {
+		t.Errorf("Expected ErrExpectedVersionFailed and got %v", err)
+	}
+}
+
+func TestWriteMapsDuplicateMessageIDErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRepository(ctrl)
+
+	msg := getSampleCommand()
+	ctx := context.Background()
+
+	msgEnv := getSampleCommandAsEnvelopeEntityIDMissing()
+
+	mockRepo.
+		EXPECT().
+		WriteMessage(ctx, msgEnv).
+		Return(repository.ErrDuplicateMessageID)
+
+	var logrusLogger = logrus.New()
+	logrusLogger.Out = ioutil.Discard
+	myMessageStore := NewMessageStoreFromRepository(mockRepo, logrusLogger)
+	err := myMessageStore.Write(ctx, msg)
+
+	if err != ErrDuplicateMessageID {
+		t.Errorf("Expected ErrDuplicateMessageID and got %v", err)
+	}
+}
+
+func TestWriteToMockMessageStoreAtWrongPositionFails(t *testing.T) {
+	ctx := context.Background()
+
+	myMessageStore := NewMockMessageStoreWithMessages(eventsToMessageSlice(getSampleEvents()))
+	err := myMessageStore.Write(ctx, getSampleEvent(), AtPosition(2))
+
+	if err != ErrExpectedVersionFailed {
+		t.Errorf("Expected ErrExpectedVersionFailed and got %v", err)
+	}
+}
+
 func TestAtPositionMatcher(t *testing.T) {
 	atPosition := AtPosition(42)
 	matcher := AtPositionMatcher{42}